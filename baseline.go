@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/errata-ai/vale/v2/config"
+	"github.com/errata-ai/vale/v2/core"
+)
+
+// defaultBaseline is the baseline file used by `vale baseline` when no other
+// path is given.
+const defaultBaseline = ".vale-baseline.json"
+
+// writeBaseline records all of the linted alerts in the configured baseline
+// file.
+func writeBaseline(linted []*core.File, cfg *config.Config) error {
+	path := cfg.Baseline
+	if path == "" {
+		path = defaultBaseline
+	}
+
+	b := core.NewBaseline(path, linted)
+	if err := b.Write(path); err != nil {
+		return err
+	}
+
+	fmt.Printf("Recorded %d alert(s) in '%s'.\n", len(b.Alerts), path)
+	return nil
+}
+
+// filterBaseline removes any alerts recorded in the configured baseline file.
+func filterBaseline(linted []*core.File, cfg *config.Config) error {
+	if !core.FileExists(cfg.Baseline) {
+		return core.NewE100(
+			"--baseline",
+			fmt.Errorf("path '%s' does not exist", cfg.Baseline))
+	}
+
+	b, err := core.ReadBaseline(cfg.Baseline)
+	if err != nil {
+		return err
+	}
+
+	b.Filter(linted)
+	return nil
+}
@@ -83,12 +83,12 @@ func NewCapitalization(cfg *config.Config, generic baseCheck) (Capitalization, e
 }
 
 // Run checks the capitalization style of the provided text.
-func (o Capitalization) Run(txt string, f *core.File) []core.Alert {
+func (o Capitalization) Run(txt string, f *core.File) ([]core.Alert, error) {
 	alerts := []core.Alert{}
 	if !o.Check(txt, o.Exceptions, o.exceptRe) {
 		alerts = append(alerts, makeAlert(o.Definition, []int{0, len(txt)}, txt))
 	}
-	return alerts
+	return alerts, nil
 }
 
 // Fields provides access to the internal rule definition.
@@ -59,7 +59,7 @@ func NewConditional(cfg *config.Config, generic baseCheck) (Conditional, error)
 }
 
 // Run evalutes the given conditional statement.
-func (c Conditional) Run(txt string, f *core.File) []core.Alert {
+func (c Conditional) Run(txt string, f *core.File) ([]core.Alert, error) {
 	alerts := []core.Alert{}
 
 	// We first look for the consequent of the conditional statement.
@@ -90,7 +90,7 @@ func (c Conditional) Run(txt string, f *core.File) []core.Alert {
 		}
 	}
 
-	return alerts
+	return alerts, nil
 }
 
 // Fields provides access to the internal rule definition.
@@ -72,7 +72,7 @@ func NewConsistency(cfg *config.Config, generic baseCheck) (Consistency, error)
 }
 
 // Run looks for inconsistent use of a user-defined regex.
-func (o Consistency) Run(txt string, f *core.File) []core.Alert {
+func (o Consistency) Run(txt string, f *core.File) ([]core.Alert, error) {
 	alerts := []core.Alert{}
 	loc := []int{}
 
@@ -93,7 +93,7 @@ func (o Consistency) Run(txt string, f *core.File) []core.Alert {
 		}
 	}
 
-	return alerts
+	return alerts, nil
 }
 
 // Fields provides access to the internal rule definition.
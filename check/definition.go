@@ -1,6 +1,7 @@
 package check
 
 import (
+	"errors"
 	"fmt"
 	"sort"
 	"strconv"
@@ -9,12 +10,13 @@ import (
 	"github.com/errata-ai/vale/v2/config"
 	"github.com/errata-ai/vale/v2/core"
 	"github.com/jdkato/regexp"
+	"github.com/mitchellh/mapstructure"
 	"gopkg.in/yaml.v2"
 )
 
 // Rule represents in individual writing construct to enforce.
 type Rule interface {
-	Run(text string, file *core.File) []core.Alert
+	Run(text string, file *core.File) ([]core.Alert, error)
 	Fields() Definition
 	Pattern() string
 }
@@ -34,6 +36,11 @@ type Definition struct {
 }
 
 var defaultStyles = []string{"Vale"}
+
+// pluginStyle is the name of the directory, on `StylesPath`, that holds
+// plugin executables.
+const pluginStyle = "plugins"
+
 var extensionPoints = []string{
 	"capitalization",
 	"conditional",
@@ -119,6 +126,50 @@ func buildRule(cfg *config.Config, generic baseCheck) (Rule, error) {
 	}
 }
 
+// ruleTypes create an empty value of the type that each extension point's
+// definitions are decoded into.
+var ruleTypes = map[string]func() interface{}{
+	"capitalization": func() interface{} { return &Capitalization{} },
+	"conditional":    func() interface{} { return &Conditional{} },
+	"consistency":    func() interface{} { return &Consistency{} },
+	"existence":      func() interface{} { return &Existence{} },
+	"lt":             func() interface{} { return &LanguageTool{} },
+	"occurrence":     func() interface{} { return &Occurrence{} },
+	"readability":    func() interface{} { return &Readability{} },
+	"repetition":     func() interface{} { return &Repetition{} },
+	"sequence":       func() interface{} { return &Sequence{} },
+	"spelling":       func() interface{} { return &Spelling{} },
+	"substitution":   func() interface{} { return &Substitution{} },
+}
+
+// decodeOptions decodes opts into the type of the given extension point,
+// failing if any of them isn't one of its options (or has the wrong type).
+func decodeOptions(extends string, opts map[string]interface{}) error {
+	newRule, found := ruleTypes[extends]
+	if !found {
+		// `buildRule` reports unknown extension points.
+		return nil
+	}
+
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		ErrorUnused: true,
+		Result:      newRule(),
+	})
+	if err != nil {
+		return err
+	}
+
+	err = decoder.Decode(opts)
+	if merr, ok := err.(*mapstructure.Error); ok {
+		msgs := []string{}
+		for _, msg := range merr.Errors {
+			msgs = append(msgs, strings.Replace(msg, "'' has invalid keys", "unknown options", 1))
+		}
+		return errors.New(strings.Join(msgs, "; "))
+	}
+	return err
+}
+
 func formatMessages(msg string, desc string, subs ...string) (string, string) {
 	return core.FormatMessage(msg, subs...), core.FormatMessage(desc, subs...)
 }
@@ -83,10 +83,13 @@ func NewExistence(cfg *config.Config, generic baseCheck) (Existence, error) {
 // This is simplest of the available extension points: it looks for any matches
 // of its internal `pattern` (calculated from `NewExistence`) against the
 // provided text.
-func (e Existence) Run(text string, file *core.File) []core.Alert {
+func (e Existence) Run(text string, file *core.File) ([]core.Alert, error) {
 	alerts := []core.Alert{}
 
-	locs := e.pattern.FindAllStringIndex(text, -1)
+	var locs [][]int
+	file.TimeMatch(e.Name, func() {
+		locs = e.pattern.FindAllStringIndex(text, -1)
+	})
 	for _, loc := range locs {
 		match := text[loc[0]:loc[1]]
 		if !e.hasExceptions || !e.exceptRe.MatchString(match) {
@@ -94,7 +97,7 @@ func (e Existence) Run(text string, file *core.File) []core.Alert {
 		}
 	}
 
-	return alerts
+	return alerts, nil
 }
 
 // Fields provides access to the internal rule definition.
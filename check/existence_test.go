@@ -29,7 +29,10 @@ func TestExistence(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	alerts := rule.Run("This is a test.", file)
+	alerts, err := rule.Run("This is a test.", file)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	if len(alerts) != 1 {
 		t.Errorf("expected one alert, not %v", alerts)
@@ -29,13 +29,8 @@ func NewLanguageTool(cfg *config.Config, generic baseCheck) (LanguageTool, error
 }
 
 // Run sends the given text to an instance of LanguageTool.
-func (l LanguageTool) Run(text string, file *core.File) []core.Alert {
-	alerts, err := rule.CheckWithLT(text, file, l.config)
-	if err != nil {
-		// FIXME: don't panic here
-		panic(err)
-	}
-	return alerts
+func (l LanguageTool) Run(text string, file *core.File) ([]core.Alert, error) {
+	return rule.CheckWithLT(text, file, l.config)
 }
 
 // Fields provides access to the internal rule definition.
@@ -11,27 +11,30 @@ import (
 	"github.com/errata-ai/vale/v2/config"
 	"github.com/errata-ai/vale/v2/core"
 	"github.com/errata-ai/vale/v2/rule"
+	"gopkg.in/yaml.v2"
 )
 
 // Manager controls the loading and validating of the check extension points.
 type Manager struct {
 	Config *config.Config
 
-	scopes map[string]struct{}
-	rules  map[string]Rule
-	styles []string
+	scopes      map[string]struct{}
+	rules       map[string]Rule
+	definitions map[string]baseCheck // rule name -> definition (for variants)
+	styles      []string
+	variants    map[string]map[string]Rule // section -> rule name -> rule
 }
 
 // NewManager creates a new Manager and loads the rule definitions (that is,
 // extended checks) specified by configuration.
 func NewManager(config *config.Config) (*Manager, error) {
-	var path string
-
 	mgr := Manager{
 		Config: config,
 
-		rules:  make(map[string]Rule),
-		scopes: make(map[string]struct{}),
+		rules:       make(map[string]Rule),
+		scopes:      make(map[string]struct{}),
+		definitions: make(map[string]baseCheck),
+		variants:    make(map[string]map[string]Rule),
 	}
 
 	err := mgr.loadDefaultRules()
@@ -39,31 +42,67 @@ func NewManager(config *config.Config) (*Manager, error) {
 		return &mgr, err
 	}
 
+	// Load any external plugins ...
+	err = mgr.loadPlugins(mgr.Config.Styles, mgr.Config.Checks)
+	if err != nil {
+		return &mgr, err
+	}
+
 	// Load our styles ...
 	err = mgr.loadStyles(mgr.Config.Styles)
+	if err == nil {
+		// ... and any remaining individual rules.
+		err = mgr.loadChecks(mgr.Config.Checks)
+	}
+
 	if err != nil {
-		return &mgr, err
+		// The caller won't use a manager that failed to load, so we don't
+		// leave its plugins running.
+		mgr.Close()
 	}
+	return &mgr, err
+}
 
-	for _, chk := range mgr.Config.Checks {
-		// Load any remaining individual rules.
-		if !strings.Contains(chk, ".") {
-			// A rule must be associated with a style (i.e., "Style[.]Rule").
-			continue
-		}
-		parts := strings.Split(chk, ".")
-		if !mgr.hasStyle(parts[0]) {
-			// If this rule isn't part of an already-loaded style, we load it
-			// individually.
-			fName := parts[1] + ".yml"
-			path = filepath.Join(mgr.Config.StylesPath, parts[0], fName)
-			if err = mgr.addRuleFromSource(fName, path); err != nil {
-				return &mgr, err
+// Close releases the resources held by the manager's rules, stopping the
+// processes of any plugins.
+func (mgr *Manager) Close() error {
+	var first error
+	for _, rule := range mgr.rules {
+		if p, ok := rule.(Plugin); ok {
+			if err := p.Close(); err != nil && first == nil {
+				first = err
 			}
 		}
 	}
+	return first
+}
 
-	return &mgr, err
+// AddConfig loads any styles and individual rules referenced by cfg that
+// haven't already been loaded (e.g., from a nested configuration file), along
+// with the variants for any rule options that it overrides (see
+// `NestedSection`).
+func (mgr *Manager) AddConfig(cfg *config.Config) error {
+	if err := mgr.loadPlugins(cfg.Styles, cfg.Checks); err != nil {
+		return err
+	} else if err = mgr.loadStyles(cfg.Styles); err != nil {
+		return err
+	} else if err = mgr.loadChecks(cfg.Checks); err != nil {
+		return err
+	}
+
+	for chkName, generic := range mgr.definitions {
+		if err := mgr.addVariants(cfg, chkName, generic); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NestedSection is the name by which `Rule` knows the section sec of the
+// nested configuration cfg, which keeps it apart from a section of the same
+// name in another configuration.
+func NestedSection(cfg *config.Config, sec string) string {
+	return cfg.Path + "\x00" + sec
 }
 
 // AddRule adds the given rule to the manager.
@@ -89,6 +128,16 @@ func (mgr *Manager) Rules() map[string]Rule {
 	return mgr.rules
 }
 
+// Rule returns the named rule as configured for the given section: if the
+// section overrides any of the rule's options (e.g., `Style.Rule.max = 30`),
+// this is a variant built with those options.
+func (mgr *Manager) Rule(name, sec string) Rule {
+	if rule, found := mgr.variants[sec][name]; found {
+		return rule
+	}
+	return mgr.rules[name]
+}
+
 // HasScope returns `true` if the manager has a rule that applies to `scope`.
 func (mgr *Manager) HasScope(scope string) bool {
 	_, found := mgr.scopes[scope]
@@ -151,9 +200,108 @@ func (mgr *Manager) addCheck(file []byte, chkName, path string) error {
 	base := strings.Split(generic["scope"].(string), ".")[0]
 	mgr.scopes[base] = struct{}{}
 
+	mgr.definitions[chkName] = generic
+	if err = mgr.addVariants(mgr.Config, chkName, generic); err != nil {
+		return err
+	}
+
 	return mgr.AddRule(chkName, rule)
 }
 
+// addVariants builds a copy of the given rule for each section of cfg that
+// overrides any of its options.
+//
+// A section's options are applied on top of its configuration's global
+// (`[*]`) ones, if any. A nested configuration's options are also applied on
+// top of the root configuration's global ones.
+func (mgr *Manager) addVariants(cfg *config.Config, chkName string, generic baseCheck) error {
+	nested := cfg != mgr.Config
+
+	for sec, rules := range cfg.RuleOpts {
+		opts, found := rules[chkName]
+		if !found {
+			continue
+		}
+
+		layers := []map[string]string{}
+		if nested {
+			layers = append(layers, mgr.Config.RuleOpts["*"][chkName])
+		}
+		if sec != "*" {
+			layers = append(layers, cfg.RuleOpts["*"][chkName])
+		}
+		layers = append(layers, opts)
+
+		variant := baseCheck{}
+		for k, v := range generic {
+			variant[k] = v
+		}
+		for _, layer := range layers {
+			if err := setOptions(variant, chkName, layer, cfg.Path); err != nil {
+				return err
+			}
+		}
+
+		rule, err := buildRule(mgr.Config, variant)
+		if err != nil {
+			return err
+		}
+
+		base := strings.Split(variant["scope"].(string), ".")[0]
+		mgr.scopes[base] = struct{}{}
+
+		if nested {
+			sec = NestedSection(cfg, sec)
+		}
+		if _, found := mgr.variants[sec]; !found {
+			mgr.variants[sec] = make(map[string]Rule)
+		}
+		mgr.variants[sec][chkName] = rule
+	}
+	return nil
+}
+
+// setOptions overrides the options of a rule definition, parsing each value
+// as YAML (so that, e.g., `max = 30` is an integer and `tokens = [a, b]` is a
+// list).
+//
+// path is the configuration file that the options come from.
+func setOptions(generic baseCheck, chkName string, opts map[string]string, path string) error {
+	parsed := map[string]interface{}{}
+	for opt, value := range opts {
+		key := chkName + "." + opt
+		if core.StringInSlice(opt, []string{"extends", "name", "path"}) {
+			return core.NewE201FromTarget(
+				fmt.Sprintf("The '%s' option can't be overridden.", opt),
+				key,
+				path)
+		}
+
+		var v interface{}
+		if err := yaml.Unmarshal([]byte(value), &v); err != nil {
+			return core.NewE201FromTarget(
+				fmt.Sprintf("The value of '%s' isn't valid YAML: %v.", key, err),
+				key,
+				path)
+		}
+		parsed[opt] = v
+	}
+
+	// Make sure that every option exists (and has the right type) for the
+	// rule's extension point, since a typo would otherwise be ignored.
+	if err := decodeOptions(generic["extends"].(string), parsed); err != nil {
+		return core.NewE201FromTarget(
+			fmt.Sprintf("The options of '%s' are invalid: %v", chkName, err),
+			chkName,
+			path)
+	}
+
+	for opt, v := range parsed {
+		generic[opt] = v
+	}
+	return nil
+}
+
 func (mgr *Manager) loadDefaultRules() error {
 	for _, style := range defaultStyles {
 		if core.StringInSlice(style, mgr.styles) {
@@ -219,6 +367,93 @@ func (mgr *Manager) loadStyles(styles []string) error {
 	return nil
 }
 
+func (mgr *Manager) loadChecks(checks []string) error {
+	for _, chk := range checks {
+		if !strings.Contains(chk, ".") {
+			// A rule must be associated with a style (i.e., "Style[.]Rule").
+			continue
+		}
+		parts := strings.Split(chk, ".")
+		if !mgr.hasStyle(parts[0]) {
+			// If this rule isn't part of an already-loaded style, we load it
+			// individually.
+			fName := parts[1] + ".yml"
+			path := filepath.Join(mgr.Config.StylesPath, parts[0], fName)
+			if err := mgr.addRuleFromSource(fName, path); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// loadPlugins starts the plugins, stored in the `plugins` directory on
+// `StylesPath`, that are enabled by the given styles and checks and haven't
+// already been started.
+//
+// Plugins are loaded as a style named "plugins", so they can be enabled all
+// at once (`BasedOnStyles = plugins`) or individually (`plugins.Name = YES`).
+func (mgr *Manager) loadPlugins(styles, checks []string) error {
+	if mgr.Config.StylesPath == "" {
+		return nil
+	}
+
+	dir := filepath.Join(mgr.Config.StylesPath, pluginStyle)
+	if !core.IsDir(dir) {
+		return nil
+	}
+
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return core.NewE100("loadPlugins", err)
+	}
+
+	all := core.StringInSlice(pluginStyle, styles)
+	for _, fi := range entries {
+		if !isPlugin(fi) {
+			continue
+		}
+
+		name := strings.TrimSuffix(fi.Name(), filepath.Ext(fi.Name()))
+		chkName := pluginStyle + "." + name
+		if !all && !core.StringInSlice(chkName, checks) {
+			continue
+		} else if _, found := mgr.rules[chkName]; found {
+			// A previous configuration has already started it.
+			continue
+		}
+
+		generic := baseCheck{"name": chkName, "path": filepath.Join(dir, fi.Name())}
+		if level, ok := mgr.Config.RuleToLevel[chkName]; ok {
+			generic["level"] = level
+		}
+
+		rule, err := NewPlugin(mgr.Config, generic)
+		if err != nil {
+			mgr.Close()
+			return core.NewE100(
+				"loadPlugins",
+				fmt.Errorf("failed to start plugin '%s': %v", chkName, err))
+		}
+
+		base := strings.Split(rule.Scope, ".")[0]
+		mgr.scopes[base] = struct{}{}
+
+		if err = mgr.AddRule(chkName, rule); err != nil {
+			rule.Close()
+			mgr.Close()
+			return err
+		}
+	}
+
+	// NOTE: This ensures that we don't look for YAML-based rules in the
+	// plugin directory.
+	if !mgr.hasStyle(pluginStyle) {
+		mgr.styles = append(mgr.styles, pluginStyle)
+	}
+	return nil
+}
+
 func (mgr *Manager) loadVocabRules() {
 	if len(mgr.Config.AcceptedTokens) > 0 {
 		vocab := defaultRules["Terms"]
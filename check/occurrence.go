@@ -51,10 +51,13 @@ func NewOccurrence(cfg *config.Config, generic baseCheck) (Occurrence, error) {
 
 // Run checks the number of occurrences of a user-defined regex against a
 // certain threshold.
-func (o Occurrence) Run(txt string, f *core.File) []core.Alert {
+func (o Occurrence) Run(txt string, f *core.File) ([]core.Alert, error) {
 	alerts := []core.Alert{}
 
-	locs := o.pattern.FindAllStringIndex(txt, -1)
+	var locs [][]int
+	f.TimeMatch(o.Name, func() {
+		locs = o.pattern.FindAllStringIndex(txt, -1)
+	})
 	occurrences := len(locs)
 	if occurrences > o.Max || occurrences < o.Min {
 		// NOTE: We take only the first match (`locs[0]`) instead of the whole
@@ -67,7 +70,7 @@ func (o Occurrence) Run(txt string, f *core.File) []core.Alert {
 		alerts = append(alerts, a)
 	}
 
-	return alerts
+	return alerts, nil
 }
 
 // Fields provides access to the internal rule definition.
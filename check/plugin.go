@@ -0,0 +1,297 @@
+package check
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/errata-ai/vale/v2/config"
+	"github.com/errata-ai/vale/v2/core"
+	"github.com/mitchellh/mapstructure"
+)
+
+// Plugin is a rule implemented by an external executable.
+//
+// Each plugin runs in its own process, which means that it can be written in
+// any language (and, for Go, built with any toolchain). See
+// `core.PluginRequest` for a description of the protocol.
+type Plugin struct {
+	Definition `mapstructure:",squash"`
+
+	proc *pluginProcess
+}
+
+type pluginProcess struct {
+	sync.Mutex
+
+	path    string
+	timeout time.Duration // how long to wait for a response (0 for no limit)
+
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Scanner
+	closed bool
+}
+
+// pluginTimeout is how long, in seconds, a plugin has to respond to a request
+// if `ProcessTimeout` hasn't been set.
+const pluginTimeout = 60
+
+// pluginGracePeriod is how long a plugin has to exit, once its input has
+// been closed, before we kill it.
+const pluginGracePeriod = 5 * time.Second
+
+// NewPlugin starts the plugin executable given by `path` and creates a new
+// `Rule` from the Scope and Level it describes.
+func NewPlugin(cfg *config.Config, generic baseCheck) (Plugin, error) {
+	rule := Plugin{}
+	path := generic["path"].(string)
+
+	timeout := time.Duration(cfg.ProcessTimeout(pluginTimeout)) * time.Second
+
+	proc, err := startPlugin(path, timeout)
+	if err != nil {
+		return rule, err
+	}
+
+	desc, err := proc.call(core.PluginRequest{Method: "describe"})
+	if err != nil {
+		proc.close()
+		return rule, err
+	}
+
+	if _, ok := generic["level"]; !ok {
+		generic["level"] = desc.Level
+	}
+	if _, ok := generic["scope"]; !ok {
+		generic["scope"] = desc.Scope
+	}
+
+	if err = mapstructure.Decode(generic, &rule); err != nil {
+		proc.close()
+		return rule, readStructureError(err, path)
+	}
+
+	if !core.StringInSlice(rule.Level, core.AlertLevels) {
+		rule.Level = "warning"
+	}
+	if rule.Scope == "" {
+		rule.Scope = "text"
+	}
+
+	rule.proc = proc
+	return rule, nil
+}
+
+// Run sends the given text to the plugin's process.
+func (p Plugin) Run(text string, file *core.File) ([]core.Alert, error) {
+	alerts := []core.Alert{}
+
+	resp, err := p.proc.call(core.PluginRequest{
+		Method: "run",
+		Text:   text,
+		Path:   file.Path,
+	})
+	if err != nil {
+		return alerts, err
+	}
+
+	for _, a := range resp.Alerts {
+		if len(a.Span) != 2 {
+			continue
+		}
+
+		start, end := a.Span[0], a.Span[1]
+		if a.Match == "" && start >= 0 && start <= end && end <= len(text) {
+			// We need the matched text to calculate the alert's location.
+			a.Match = text[start:end]
+		}
+		if a.Link == "" {
+			a.Link = p.Link
+		}
+		a.Check = p.Name
+
+		alerts = append(alerts, a)
+	}
+
+	return alerts, nil
+}
+
+// Close stops the plugin's process.
+func (p Plugin) Close() error {
+	if p.proc == nil {
+		return nil
+	}
+	return p.proc.close()
+}
+
+// Fields provides access to the internal rule definition.
+func (p Plugin) Fields() Definition {
+	return p.Definition
+}
+
+// Pattern is the internal regex pattern used by this rule.
+func (p Plugin) Pattern() string {
+	return ""
+}
+
+func startPlugin(path string, timeout time.Duration) (*pluginProcess, error) {
+	p := &pluginProcess{path: path, timeout: timeout}
+	return p, p.start()
+}
+
+// start runs the plugin's executable.
+func (p *pluginProcess) start() error {
+	cmd := exec.Command(p.path)
+	cmd.Stderr = os.Stderr
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+
+	if err = cmd.Start(); err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), 1<<30)
+
+	p.cmd, p.stdin, p.stdout = cmd, stdin, scanner
+	return nil
+}
+
+// call sends req to the process and waits for its response.
+//
+// If the process doesn't respond within `ProcessTimeout` (or
+// `pluginTimeout`), we kill it and start a new one (so that the next call has a chance of succeeding).
+func (p *pluginProcess) call(req core.PluginRequest) (core.PluginResponse, error) {
+	var resp core.PluginResponse
+
+	p.Lock()
+	defer p.Unlock()
+
+	b, err := json.Marshal(req)
+	if err != nil {
+		return resp, err
+	} else if p.closed {
+		return resp, errors.New("the plugin has been closed")
+	}
+
+	done := make(chan pluginReply, 1)
+	go func(stdin io.Writer, stdout *bufio.Scanner) {
+		done <- exchange(stdin, stdout, b)
+	}(p.stdin, p.stdout)
+
+	var reply pluginReply
+	if p.timeout <= 0 {
+		reply = <-done
+	} else {
+		select {
+		case reply = <-done:
+		case <-time.After(p.timeout):
+			return resp, p.restart()
+		}
+	}
+
+	if reply.err != nil {
+		return resp, reply.err
+	} else if err = json.Unmarshal(reply.line, &resp); err != nil {
+		return resp, err
+	} else if resp.Error != "" {
+		return resp, errors.New(resp.Error)
+	}
+
+	return resp, nil
+}
+
+// A pluginReply is a line read from a plugin's output.
+type pluginReply struct {
+	line []byte
+	err  error
+}
+
+// exchange writes the request, b, to stdin and reads the response from
+// stdout.
+func exchange(stdin io.Writer, stdout *bufio.Scanner, b []byte) pluginReply {
+	if _, err := stdin.Write(append(b, '\n')); err != nil {
+		return pluginReply{err: err}
+	} else if !stdout.Scan() {
+		err = stdout.Err()
+		if err == nil {
+			err = errors.New("the plugin exited unexpectedly")
+		}
+		return pluginReply{err: err}
+	}
+	return pluginReply{line: append([]byte{}, stdout.Bytes()...)}
+}
+
+// restart replaces a process that has timed out, returning the error to
+// report for the request that it didn't answer.
+func (p *pluginProcess) restart() error {
+	timedOut := fmt.Errorf(
+		"timed out after %ds (see ProcessTimeout)", int(p.timeout/time.Second))
+
+	p.stdin.Close()
+	_ = p.cmd.Process.Kill()
+	_ = p.cmd.Wait()
+
+	if err := p.start(); err != nil {
+		p.closed = true
+		return fmt.Errorf("%v; failed to restart the plugin: %v", timedOut, err)
+	}
+	return timedOut
+}
+
+// close asks the process to exit, by closing its input, and waits for it to
+// do so -- killing it if it doesn't within `pluginGracePeriod`.
+func (p *pluginProcess) close() error {
+	p.Lock()
+	defer p.Unlock()
+
+	if p.closed {
+		return nil
+	}
+	p.closed = true
+
+	p.stdin.Close()
+
+	done := make(chan error, 1)
+	go func() { done <- p.cmd.Wait() }()
+
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(pluginGracePeriod):
+		if err := p.cmd.Process.Kill(); err != nil {
+			return err
+		}
+		<-done
+		return nil
+	}
+}
+
+// isPlugin determines if fi looks like a plugin executable.
+func isPlugin(fi os.FileInfo) bool {
+	if fi.IsDir() {
+		return false
+	} else if runtime.GOOS == "windows" {
+		ext := strings.ToLower(filepath.Ext(fi.Name()))
+		return core.StringInSlice(ext, []string{".exe", ".bat", ".cmd"})
+	}
+	return fi.Mode()&0111 != 0
+}
@@ -0,0 +1,187 @@
+package check
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/errata-ai/vale/v2/config"
+	"github.com/errata-ai/vale/v2/core"
+)
+
+// TestMain allows the test binary to double as a plugin executable.
+func TestMain(m *testing.M) {
+	if os.Getenv("VALE_TEST_PLUGIN") == "1" {
+		_ = core.ServePlugin(core.Plugin{
+			Scope: "heading",
+			Level: "error",
+			Rule: func(text string, file *core.File) []core.Alert {
+				alerts := []core.Alert{}
+				if strings.Contains(text, "hang") {
+					time.Sleep(time.Minute)
+				} else if idx := strings.Index(text, "dialog"); idx >= 0 {
+					alerts = append(alerts, core.Alert{
+						Span:    []int{idx, idx + 6},
+						Message: "Don't use 'dialog' in " + file.Path})
+				}
+				return alerts
+			},
+		})
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func TestPlugin(t *testing.T) {
+	cfg, err := config.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	os.Setenv("VALE_TEST_PLUGIN", "1")
+	defer os.Unsetenv("VALE_TEST_PLUGIN")
+
+	rule, err := NewPlugin(cfg, baseCheck{
+		"name": "plugins.Test",
+		"path": os.Args[0],
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rule.Scope != "heading" || rule.Level != "error" {
+		t.Errorf("unexpected definition: %v", rule.Fields())
+	}
+
+	defer rule.Close()
+
+	alerts, err := rule.Run("Click on the dialog bar.", &core.File{Path: "test.md"})
+	if err != nil {
+		t.Fatal(err)
+	} else if len(alerts) != 1 {
+		t.Fatalf("expected one alert, not %v", alerts)
+	}
+
+	a := alerts[0]
+	if a.Match != "dialog" || a.Check != "plugins.Test" {
+		t.Errorf("unexpected alert: %v", a)
+	} else if a.Message != "Don't use 'dialog' in test.md" {
+		t.Errorf("unexpected message: %s", a.Message)
+	}
+}
+
+func TestPluginClose(t *testing.T) {
+	cfg, err := config.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	os.Setenv("VALE_TEST_PLUGIN", "1")
+	defer os.Unsetenv("VALE_TEST_PLUGIN")
+
+	rule, err := NewPlugin(cfg, baseCheck{
+		"name": "plugins.Test",
+		"path": os.Args[0],
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The plugin exits once its input is closed, so there's nothing left to
+	// wait on (or kill).
+	if err = rule.Close(); err != nil {
+		t.Fatal(err)
+	} else if state := rule.proc.cmd.ProcessState; state == nil || !state.Exited() {
+		t.Fatalf("expected the plugin to have exited, got %v", state)
+	}
+
+	// A closed (or dead) plugin is an error on the file being linted, not a
+	// reason to stop everything.
+	if _, err = rule.Run("Click on the dialog bar.", &core.File{Path: "test.md"}); err == nil {
+		t.Error("expected an error from a closed plugin")
+	}
+	if err = rule.Close(); err != nil {
+		t.Errorf("expected closing twice to be a no-op, got %v", err)
+	}
+}
+
+func TestPluginTimeout(t *testing.T) {
+	cfg, err := config.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Timeout = 1
+
+	os.Setenv("VALE_TEST_PLUGIN", "1")
+	defer os.Unsetenv("VALE_TEST_PLUGIN")
+
+	rule, err := NewPlugin(cfg, baseCheck{
+		"name": "plugins.Test",
+		"path": os.Args[0],
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rule.Close()
+
+	_, err = rule.Run("Don't hang.", &core.File{Path: "test.md"})
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("expected a timeout, got %v", err)
+	}
+
+	// The plugin has been restarted, so the next file is linted as usual.
+	alerts, err := rule.Run("Click on the dialog bar.", &core.File{Path: "test.md"})
+	if err != nil {
+		t.Fatal(err)
+	} else if len(alerts) != 1 {
+		t.Errorf("expected one alert, not %v", alerts)
+	}
+}
+
+func TestPluginNested(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "plugins"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	exe, err := filepath.Abs(os.Args[0])
+	if err != nil {
+		t.Fatal(err)
+	} else if err = os.Symlink(exe, filepath.Join(dir, "plugins", "Test")); err != nil {
+		t.Skip("symlinks aren't supported:", err)
+	}
+
+	os.Setenv("VALE_TEST_PLUGIN", "1")
+	defer os.Unsetenv("VALE_TEST_PLUGIN")
+
+	cfg, err := config.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.StylesPath = dir
+
+	mgr, err := NewManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mgr.Close()
+
+	if _, found := mgr.Rules()["plugins.Test"]; found {
+		t.Fatal("expected the plugin to be disabled by the root configuration")
+	}
+
+	// A nested configuration can enable a plugin that the root doesn't.
+	nested, err := config.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	nested.Checks = []string{"plugins.Test"}
+
+	if err = mgr.AddConfig(nested); err != nil {
+		t.Fatal(err)
+	} else if _, found := mgr.Rules()["plugins.Test"]; !found {
+		t.Error("expected the nested configuration to start the plugin")
+	}
+}
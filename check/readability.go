@@ -42,7 +42,7 @@ func NewReadability(cfg *config.Config, generic baseCheck) (Readability, error)
 }
 
 // Run calculates the readability level of the given text.
-func (o Readability) Run(txt string, f *core.File) []core.Alert {
+func (o Readability) Run(txt string, f *core.File) ([]core.Alert, error) {
 	var grade float64
 	alerts := []core.Alert{}
 
@@ -72,7 +72,7 @@ func (o Readability) Run(txt string, f *core.File) []core.Alert {
 		alerts = append(alerts, a)
 	}
 
-	return alerts
+	return alerts, nil
 }
 
 // Fields provides access to the internal rule definition.
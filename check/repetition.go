@@ -52,14 +52,19 @@ func NewRepetition(cfg *config.Config, generic baseCheck) (Repetition, error) {
 // Run executes the the `repetition`-based rule.
 //
 // The rule looks for repeated matches of its regex -- such as "this this".
-func (o Repetition) Run(txt string, f *core.File) []core.Alert {
+func (o Repetition) Run(txt string, f *core.File) ([]core.Alert, error) {
 	var curr, prev string
 	var hit bool
 	var ploc []int
 	var count int
 
+	var locs [][]int
+	f.TimeMatch(o.Name, func() {
+		locs = o.pattern.FindAllStringIndex(txt, -1)
+	})
+
 	alerts := []core.Alert{}
-	for _, loc := range o.pattern.FindAllStringIndex(txt, -1) {
+	for _, loc := range locs {
 		curr = strings.TrimSpace(txt[loc[0]:loc[1]])
 		if o.Ignorecase {
 			hit = strings.ToLower(curr) == strings.ToLower(prev) && curr != ""
@@ -86,7 +91,7 @@ func (o Repetition) Run(txt string, f *core.File) []core.Alert {
 		prev = curr
 	}
 
-	return alerts
+	return alerts, nil
 }
 
 // Fields provides access to the internal rule definition.
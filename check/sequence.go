@@ -181,7 +181,7 @@ func stepsToString(steps []string) string {
 }
 
 // Run looks for the user-defined sequence of tokens.
-func (s Sequence) Run(txt string, f *core.File) []core.Alert {
+func (s Sequence) Run(txt string, f *core.File) ([]core.Alert, error) {
 	var alerts []core.Alert
 
 	for idx, tok := range s.Tokens {
@@ -211,5 +211,5 @@ func (s Sequence) Run(txt string, f *core.File) []core.Alert {
 		}
 	}
 
-	return alerts
+	return alerts, nil
 }
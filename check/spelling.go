@@ -147,7 +147,7 @@ func NewSpelling(cfg *config.Config, generic baseCheck) (Spelling, error) {
 }
 
 // Run performs spell-checking on the provided text.
-func (s Spelling) Run(txt string, f *core.File) []core.Alert {
+func (s Spelling) Run(txt string, f *core.File) ([]core.Alert, error) {
 	alerts := []core.Alert{}
 
 	// This ensures that we respect `.aff` entries like `ICONV ’ '`,
@@ -179,7 +179,7 @@ OUTER:
 		}
 	}
 
-	return alerts
+	return alerts, nil
 }
 
 // Fields provides access to the internal rule definition.
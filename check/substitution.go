@@ -46,9 +46,7 @@ func NewSubstitution(cfg *config.Config, generic baseCheck) (Substitution, error
 
 	replacements := []string{}
 	for regexstr, replacement := range rule.Swap {
-		opens := strings.Count(regexstr, "(")
-		if opens != strings.Count(regexstr, "?:") &&
-			opens != strings.Count(regexstr, `\(`) {
+		if hasCaptureGroup(regexstr) {
 			// We rely on manually-added capture groups to associate a match
 			// with its replacement -- e.g.,
 			//
@@ -81,17 +79,20 @@ func NewSubstitution(cfg *config.Config, generic baseCheck) (Substitution, error
 // Run executes the the `substitution`-based rule.
 //
 // The rule looks for one pattern and then suggests a replacement.
-func (s Substitution) Run(txt string, f *core.File) []core.Alert {
+func (s Substitution) Run(txt string, f *core.File) ([]core.Alert, error) {
 	alerts := []core.Alert{}
 	pos := false
 
-	// Leave early if we can to avoid calling `FindAllStringSubmatchIndex`
-	// unnecessarily.
-	if !s.pattern.MatchString(txt) {
-		return alerts
-	}
+	var submats [][]int
+	f.TimeMatch(s.Name, func() {
+		// Leave early if we can to avoid calling `FindAllStringSubmatchIndex`
+		// unnecessarily.
+		if s.pattern.MatchString(txt) {
+			submats = s.pattern.FindAllStringSubmatchIndex(txt, -1)
+		}
+	})
 
-	for _, submat := range s.pattern.FindAllStringSubmatchIndex(txt, -1) {
+	for _, submat := range submats {
 		for idx, mat := range submat {
 			if mat != -1 && idx > 0 && idx%2 == 0 {
 				loc := []int{mat, submat[idx+1]}
@@ -115,11 +116,16 @@ func (s Substitution) Run(txt string, f *core.File) []core.Alert {
 						// NOTE: For backwards-compatibility, we need to ensure
 						// that we don't double quote.
 						s.Message = convertMessage(s.Message)
+					} else if action.Name == "" {
+						// Without an explicit action, the swap's replacement
+						// is the fix (see `core.FixAlert`).
+						action = core.Action{
+							Name: "replace", Params: strings.Split(expected, "|")}
 					}
 					a := core.Alert{
 						Check: s.Name, Severity: s.Level, Span: loc,
 						Link: s.Link, Hide: pos, Match: observed,
-						Action: s.Action}
+						Action: action}
 
 					a.Message, a.Description = formatMessages(s.Message,
 						s.Description, expected, observed)
@@ -130,7 +136,7 @@ func (s Substitution) Run(txt string, f *core.File) []core.Alert {
 		}
 	}
 
-	return alerts
+	return alerts, nil
 }
 
 // Fields provides access to the internal rule definition.
@@ -0,0 +1,425 @@
+package check
+
+import (
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/errata-ai/vale/v2/core"
+	"github.com/jdkato/regexp"
+	"github.com/jdkato/regexp/syntax"
+	"gopkg.in/yaml.v2"
+)
+
+// preparedKeys are the keys that are handled before a rule's definition is
+// decoded (e.g., by `addFilters` or `validateDefinition`), so they can't be
+// checked against its type. "*" applies to every extension point.
+var preparedKeys = map[string]map[string]preparedKey{
+	"*": {
+		"code": {"a boolean", func(v interface{}) bool { _, ok := v.(bool); return ok }},
+	},
+	"spelling": {
+		"filters": {"a list of strings", isStringList},
+		"ignore": {"a string or a list of strings", func(v interface{}) bool {
+			_, ok := v.(string)
+			return ok || isStringList(v)
+		}},
+	},
+}
+
+type preparedKey struct {
+	desc  string
+	valid func(interface{}) bool
+}
+
+// requiredKeys are the keys that each extension point needs in order to do
+// anything; each entry lists the alternatives that satisfy it.
+var requiredKeys = map[string][][]string{
+	"capitalization": {{"match"}},
+	"conditional":    {{"first"}, {"second"}},
+	"consistency":    {{"either"}},
+	"existence":      {{"tokens", "raw"}},
+	"occurrence":     {{"token"}, {"max", "min"}},
+	"readability":    {{"metrics"}, {"grade"}},
+	"repetition":     {{"tokens"}},
+	"sequence":       {{"tokens"}},
+	"substitution":   {{"swap"}},
+}
+
+// Validate checks the rule definition stored at path against the schema of
+// its extension point.
+//
+// Unlike loading a rule (which stops at the first problem and ignores any
+// unknown keys), we report every unknown key, wrongly-typed value, invalid
+// regular expression, and missing key that we find.
+func Validate(path string) []error {
+	file, err := ioutil.ReadFile(path)
+	if err != nil {
+		return []error{core.NewE100("Validate", err)}
+	}
+
+	generic := map[string]interface{}{}
+	if err = yaml.Unmarshal(file, &generic); err != nil {
+		r := regexp.MustCompile(`yaml: line (\d+): (.+)`)
+		if groups := r.FindStringSubmatch(err.Error()); groups != nil {
+			i, _ := strconv.Atoi(groups[1])
+			return []error{core.NewE201FromPosition(groups[2], path, i)}
+		}
+		return []error{core.NewE201FromPosition(err.Error(), path, 1)}
+	} else if err = validateDefinition(generic, path); err != nil {
+		return []error{err}
+	}
+
+	v := validator{path: path, generic: generic}
+	v.point = generic["extends"].(string)
+
+	v.checkKeys()
+	v.checkRequired()
+	v.checkPatterns()
+
+	return v.errors
+}
+
+type validator struct {
+	path    string
+	point   string
+	generic map[string]interface{}
+	errors  []error
+}
+
+func (v *validator) fail(msg, target string) {
+	v.errors = append(v.errors, core.NewE201FromTarget(msg, target, v.path))
+}
+
+// checkKeys reports any unknown keys and wrongly-typed values: anything that
+// the extension point's type (see `ruleTypes`) can't decode.
+func (v *validator) checkKeys() {
+	keys := ruleKeys(v.point)
+	for _, key := range sortedKeys(v.generic) {
+		value := v.generic[key]
+		if prepared, found := v.prepared(key); found {
+			if value != nil && !prepared.valid(value) {
+				v.fail(fmt.Sprintf("'%s' must be %s.", key, prepared.desc), key+":")
+			}
+			continue
+		}
+
+		kind, found := keys[strings.ToLower(key)]
+		if !found {
+			msg := fmt.Sprintf("'%s' isn't a valid key for '%s' rules.", key, v.point)
+			if guess := closestKey(key, v.point); guess != "" {
+				msg += fmt.Sprintf(" Did you mean '%s'?", guess)
+			}
+			v.fail(msg, key+":")
+		} else if value == nil {
+			continue
+		} else if err := decodeOptions(v.point, map[string]interface{}{key: value}); err != nil {
+			v.fail(fmt.Sprintf("'%s' must be %s.", key, describeType(kind)), key+":")
+		}
+	}
+}
+
+func (v *validator) prepared(key string) (preparedKey, bool) {
+	for _, point := range []string{"*", v.point} {
+		if prepared, found := preparedKeys[point][key]; found {
+			return prepared, true
+		}
+	}
+	return preparedKey{}, false
+}
+
+// checkRequired reports any missing keys.
+func (v *validator) checkRequired() {
+	for _, options := range requiredKeys[v.point] {
+		found := false
+		for _, key := range options {
+			if v.generic[key] != nil {
+				found = true
+			}
+		}
+
+		if !found {
+			name := "'" + options[0] + "'"
+			for _, alt := range options[1:] {
+				name += fmt.Sprintf(" (or '%s')", alt)
+			}
+			v.errors = append(v.errors, core.NewE201FromPosition(
+				fmt.Sprintf("Missing the required %s key.", name),
+				v.path,
+				1))
+		}
+	}
+}
+
+// checkPatterns reports any regular expressions that can't be compiled or
+// that contain capture groups that will never be used.
+func (v *validator) checkPatterns() {
+	for _, key := range []string{"tokens", "exceptions", "filters", "token", "first", "second"} {
+		for _, pattern := range v.strings(key) {
+			v.compile(pattern)
+		}
+	}
+
+	switch v.point {
+	case "existence":
+		if raw := v.strings("raw"); len(raw) > 0 {
+			if _, err := regexp.Compile(strings.Join(raw, "")); err != nil {
+				v.fail(fmt.Sprintf("'raw' isn't a valid regular expression: %s", err), "raw:")
+			}
+		}
+	case "capitalization":
+		if match := v.strings("match"); len(match) > 0 && !strings.HasPrefix(match[0], "$") {
+			v.compile(match[0])
+		}
+	case "conditional":
+		second := v.strings("second")
+		if len(second) > 0 && !hasCaptureGroup(second[0]) {
+			v.fail("'second' needs a capture group to identify the defined term.", "second:")
+		}
+	case "consistency":
+		either := stringMap(v.generic["either"])
+		for _, key := range sortedMapKeys(either) {
+			v.compile(key)
+			v.compile(either[key])
+		}
+	case "substitution":
+		for _, key := range sortedMapKeys(stringMap(v.generic["swap"])) {
+			if v.compile(key) && hasCaptureGroup(key) {
+				v.fail(fmt.Sprintf(
+					"'%s' contains a capture group, so it's ignored; use a non-capturing group ('(?:...)') instead.",
+					key), key)
+			}
+		}
+	case "sequence":
+		tokens, _ := v.generic["tokens"].([]interface{})
+		for _, token := range tokens {
+			if pattern, ok := stringMap(token)["pattern"]; ok {
+				v.compile(pattern)
+			}
+		}
+	}
+}
+
+// compile reports pattern if it isn't a valid regular expression.
+func (v *validator) compile(pattern string) bool {
+	if _, err := regexp.Compile(pattern); err != nil {
+		v.fail(fmt.Sprintf("'%s' isn't a valid regular expression: %s", pattern, err), pattern)
+		return false
+	}
+	return true
+}
+
+// strings returns the string value(s) of key, if there are any.
+func (v *validator) strings(key string) []string {
+	values := []string{}
+	switch t := v.generic[key].(type) {
+	case string:
+		values = append(values, t)
+	case []interface{}:
+		for _, value := range t {
+			if s, ok := value.(string); ok {
+				values = append(values, s)
+			}
+		}
+	}
+	return values
+}
+
+// ruleKeys returns the keys that the given extension point's definitions are
+// decoded from (see `ruleTypes`), along with the type of each.
+func ruleKeys(point string) map[string]reflect.Type {
+	newRule, found := ruleTypes[point]
+	if !found {
+		return map[string]reflect.Type{}
+	}
+	return structKeys(reflect.TypeOf(newRule()).Elem())
+}
+
+// structKeys mirrors how mapstructure names the fields of t: by their tag or,
+// failing that, their (case-insensitive) name.
+func structKeys(t reflect.Type) map[string]reflect.Type {
+	keys := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" || field.Type.Kind() == reflect.Func {
+			// It's unexported or can't be given in YAML.
+			continue
+		}
+
+		name, opts := field.Name, ""
+		if tag := field.Tag.Get("mapstructure"); tag != "" {
+			parts := strings.SplitN(tag, ",", 2)
+			if parts[0] != "" {
+				name = parts[0]
+			}
+			if len(parts) > 1 {
+				opts = parts[1]
+			}
+		}
+
+		if opts == "squash" && field.Type.Kind() == reflect.Struct {
+			for k, v := range structKeys(field.Type) {
+				keys[k] = v
+			}
+			continue
+		}
+		keys[strings.ToLower(name)] = field.Type
+	}
+	return keys
+}
+
+// describeType describes the YAML value expected for a field of type t.
+func describeType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Map:
+		return "a map of strings"
+	case reflect.Struct:
+		return "a map (with the keys " + keyList(t) + ")"
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Struct {
+			return "a list of maps (with the keys " + keyList(t.Elem()) + ")"
+		} else if t.Elem().Kind() == reflect.String {
+			return "a list of strings"
+		}
+		return "a list"
+	}
+	return "a " + t.String()
+}
+
+// keyList lists the keys of t -- e.g., "'name' and 'params'".
+func keyList(t reflect.Type) string {
+	keys := []string{}
+	for k := range structKeys(t) {
+		keys = append(keys, "'"+k+"'")
+	}
+	sort.Strings(keys)
+
+	if len(keys) < 2 {
+		return strings.Join(keys, "")
+	}
+	last := len(keys) - 1
+	if last == 1 {
+		return keys[0] + " and " + keys[1]
+	}
+	return strings.Join(keys[:last], ", ") + ", and " + keys[last]
+}
+
+func isStringList(value interface{}) bool {
+	list, ok := value.([]interface{})
+	if !ok {
+		return false
+	}
+	for _, item := range list {
+		if _, ok := item.(string); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// stringMap returns the string-to-string entries of a YAML map.
+func stringMap(value interface{}) map[string]string {
+	entries := map[string]string{}
+	if m, ok := value.(map[interface{}]interface{}); ok {
+		for k, v := range m {
+			ks, kok := k.(string)
+			vs, vok := v.(string)
+			if kok && vok {
+				entries[ks] = vs
+			}
+		}
+	}
+	return entries
+}
+
+// hasCaptureGroup determines if the pattern s contains a capture group.
+//
+// NOTE: `substitution` rules skip any such patterns (see `NewSubstitution`).
+func hasCaptureGroup(s string) bool {
+	re, err := syntax.Parse(s, syntax.Perl)
+	return err == nil && re.MaxCap() > 0
+}
+
+// closestKey suggests a valid key for the given extension point that's
+// similar to key (e.g., "ignorecase" for "ignorcase").
+func closestKey(key, point string) string {
+	candidates := []string{}
+	for candidate := range ruleKeys(point) {
+		candidates = append(candidates, candidate)
+	}
+	for _, p := range []string{"*", point} {
+		for candidate := range preparedKeys[p] {
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	best, bestDist := "", 3
+	for _, candidate := range candidates {
+		d := editDistance(strings.ToLower(key), candidate)
+		if d < bestDist || (d == bestDist && candidate < best) {
+			best, bestDist = candidate, d
+		}
+	}
+	return best
+}
+
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+
+	return prev[len(b)]
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := []string{}
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedMapKeys(m map[string]string) []string {
+	keys := []string{}
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,112 @@
+package check
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/errata-ai/vale/v2/core"
+)
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		rule     string
+		expected []string
+	}{
+		{
+			rule: "extends: existence\nmessage: \"'%s'\"\ntokens:\n  - foo\n",
+		},
+		{
+			rule: "extends: existence\nmessage: \"'%s'\"\nignorcase: true\ntokens:\n  - foo(\n",
+			expected: []string{
+				"'ignorcase' isn't a valid key for 'existence' rules. Did you mean 'ignorecase'?",
+				"'foo(' isn't a valid regular expression",
+			},
+		},
+		{
+			rule: "extends: substitution\nmessage: \"'%s'\"\nnonword: \"yes\"\nswap:\n  (colou?r): color\n",
+			expected: []string{
+				"'nonword' must be a boolean.",
+				"'(colou?r)' contains a capture group",
+			},
+		},
+		{
+			rule: "extends: occurrence\nmessage: Too long.\n",
+			expected: []string{
+				"Missing the required 'token' key.",
+				"Missing the required 'max' (or 'min') key.",
+			},
+		},
+		{
+			rule: "extends: sequence\nmessage: \"'%s'\"\ntokens:\n  - tag: NN\n    skp: 1\n",
+			expected: []string{
+				"'tokens' must be a list of maps (with the keys 'negate', 'pattern', 'skip', and 'tag').",
+			},
+		},
+		{
+			rule: "extends: substitution\nmessage: \"'%s'\"\nIgnoreCase: true\nswap:\n  (?i)colour: color\n  \\((colour)\\): color\n",
+			expected: []string{
+				"'\\((colour)\\)' contains a capture group",
+			},
+		},
+		{
+			rule: "extends: spelling\nmessage: \"'%s'\"\nfilters: '[pP]y.*'\nignore: ignore.txt\n",
+			expected: []string{
+				"'filters' must be a list of strings.",
+			},
+		},
+	}
+
+	dir := t.TempDir()
+	for i, c := range cases {
+		path := filepath.Join(dir, "Rule.yml")
+		if err := ioutil.WriteFile(path, []byte(c.rule), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		errs := Validate(path)
+		if len(errs) != len(c.expected) {
+			t.Errorf("case %d: expected %d error(s), got %v", i, len(c.expected), errs)
+			continue
+		}
+
+		for j, err := range errs {
+			msg := core.StripANSI(err.Error())
+			if !strings.Contains(msg, "E201") || !strings.Contains(msg, c.expected[j]) {
+				t.Errorf("case %d: expected %q, got %q", i, c.expected[j], msg)
+			}
+		}
+	}
+}
+
+func TestRequiredKeys(t *testing.T) {
+	// Every required key has to be one that the rule is decoded from.
+	for point, required := range requiredKeys {
+		keys := ruleKeys(point)
+		for _, options := range required {
+			for _, key := range options {
+				if _, found := keys[key]; !found {
+					t.Errorf("'%s' isn't a key of '%s' rules", key, point)
+				}
+			}
+		}
+	}
+}
+
+func TestHasCaptureGroup(t *testing.T) {
+	cases := map[string]bool{
+		"foo":           false,
+		"(?i)foo":       false,
+		"(?:foo|bar)":   false,
+		`\(foo\)`:       false,
+		`\((bar)`:       true,
+		"(?P<term>foo)": true,
+		"foo(bar)?":     true,
+	}
+	for pattern, expected := range cases {
+		if hasCaptureGroup(pattern) != expected {
+			t.Errorf("%q: expected = %v, got = %v", pattern, expected, !expected)
+		}
+	}
+}
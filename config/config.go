@@ -6,25 +6,34 @@ import (
 	"encoding/json"
 	"io"
 	"path/filepath"
+	"runtime"
 	"strings"
 
 	"github.com/gobwas/glob"
 	"github.com/spf13/afero"
 )
 
+// RuleOptions maps a rule's name to the options (and their values) that a
+// configuration section overrides.
+type RuleOptions map[string]map[string]string
+
 // Config ...
 type Config struct {
 	// General configuration
 	BlockIgnores   map[string][]string        // A list of blocks to ignore
 	Checks         []string                   // All checks to load
+	Concurrency    int                        // The number of files (and rules) to process in parallel
+	Exclude        []string                   // Glob patterns of paths that shouldn't be linted
 	Formats        map[string]string          // A map of unknown -> known formats
 	GBaseStyles    []string                   // Global base style
 	GChecks        map[string]bool            // Global checks
 	IgnoredClasses []string                   // A list of HTML classes to ignore
 	IgnoredScopes  []string                   // A list of HTML tags to ignore
 	MinAlertLevel  int                        // Lowest alert level to display
+	Origins        map[string]string          // The file that each setting came from
 	Path           string                     // The location of the config file
 	Project        string                     // The active project
+	RuleOpts       map[string]RuleOptions     // Section-specific rule options (e.g., "Style.Rule.max = 30")
 	RuleToLevel    map[string]string          // Single-rule level changes
 	SBaseStyles    map[string][]string        // Syntax-specific base styles
 	SChecks        map[string]map[string]bool // Syntax-specific checks
@@ -45,15 +54,37 @@ type Config struct {
 	FallbackPath string               `json:"-"`
 	FsWrapper    *afero.Afero         `json:"-"`
 	LTPath       string               `json:"-"`
+	Commands     map[string]string    `json:"-"`
 	Parsers      map[string]string    `json:"-"`
 	SecToPat     map[string]glob.Glob `json:"-"`
 	Styles       []string             `json:"-"`
-	Timeout      int                  `json:"-"`
+	Timeout      int                  `json:"-"` // seconds to wait for an external process (see `ProcessTimeout`)
 
 	// Command-line configuration
 	InExt string `json:"-"` // (optional) extension to associate with stdin
 
-	Simple bool `json:"-"` // (optional) lint all files line-by-line
+	NoCache bool `json:"-"` // (optional) don't use (or update) the results cache
+	Simple  bool `json:"-"` // (optional) lint all files line-by-line
+
+	// baseline ...
+	Baseline       string `json:"-"` // (optional) a file of pre-existing alerts to suppress
+	UpdateBaseline bool   `json:"-"` // (optional) (re-)generate the baseline file
+
+	// diff ...
+	Diff string `json:"-"` // (optional) only report alerts on the lines changed since this revision ("-" for stdin)
+
+	// lint ...
+	FilesFrom string `json:"-"` // (optional) a file (or "-" for stdin) listing the files to lint
+	Jobs      int    `json:"-"` // (optional) a CLI-provided Concurrency
+	KeepGoing bool   `json:"-"` // (optional) report per-file errors rather than stopping
+	Profile   bool   `json:"-"` // (optional) report detailed statistics for each rule
+	Rev       string `json:"-"` // (optional) lint the files as they exist at this revision
+	Staged    bool   `json:"-"` // (optional) lint the versions of files in Git's index
+	Timings   bool   `json:"-"` // (optional) report the time spent on each file and rule
+
+	// fix ...
+	DryRun bool `json:"-"` // (optional) print the fixes as a diff instead of applying them
+	Fix    bool `json:"-"` // (optional) apply each alert's action in place
 
 	// source ...
 	AlertLevel string `json:"-"` // (optional) a CLI-provided MinAlertLevel
@@ -69,7 +100,7 @@ type Config struct {
 	NoExit    bool   `json:"-"` // (optional) don't return a nonzero exit code on lint errors
 	Relative  bool   `json:"-"` // (optional) return relative paths
 	Sorted    bool   `json:"-"` // (optional) sort files by their name for output
-	Output    string `json:"-"` // (optional) output style ("line" or "CLI")
+	Output    string `json:"-"` // (optional) output style ("line", "JSON", "sarif", "junit", "checkstyle", or "CLI")
 	Wrap      bool   `json:"-"` // (optional) wrap output when CLI style
 }
 
@@ -82,7 +113,10 @@ func New() (*Config, error) {
 	cfg.SChecks = make(map[string]map[string]bool)
 	cfg.MinAlertLevel = 1
 	cfg.RuleToLevel = make(map[string]string)
+	cfg.RuleOpts = make(map[string]RuleOptions)
+	cfg.Origins = make(map[string]string)
 	cfg.Parsers = make(map[string]string)
+	cfg.Commands = make(map[string]string)
 	cfg.Stylesheets = make(map[string]string)
 	cfg.Formats = make(map[string]string)
 	cfg.BlockIgnores = make(map[string][]string)
@@ -92,11 +126,22 @@ func New() (*Config, error) {
 	cfg.RejectedTokens = make(map[string]struct{})
 	cfg.FsWrapper = &afero.Afero{Fs: afero.NewReadOnlyFs(afero.NewOsFs())}
 	cfg.LTPath = "http://localhost:8081/v2/check"
-	cfg.Timeout = 2
+	cfg.Concurrency = runtime.NumCPU()
 
 	return &cfg, nil
 }
 
+// ProcessTimeout is the number of seconds to wait for an external process
+// (e.g., a converter or a LanguageTool server): `ProcessTimeout`, if it's
+// been set, or the process's own default, def (where 0 means that we wait
+// indefinitely).
+func (c *Config) ProcessTimeout(def int) int {
+	if c.Timeout > 0 {
+		return c.Timeout
+	}
+	return def
+}
+
 // AddWordListFile adds vocab terms from a provided file.
 func (c *Config) AddWordListFile(name string, accept bool) error {
 	fd, err := c.FsWrapper.Open(name)
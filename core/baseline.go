@@ -0,0 +1,157 @@
+package core
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// baselineVersion is the version of the baseline file format.
+const baselineVersion = 1
+
+// A Baseline records the Alerts that existed when it was created, allowing
+// them to be suppressed in later runs.
+//
+// Alerts are identified by a fingerprint of their file's path, their check,
+// their matched text, and the (whitespace-normalized) line they occur on --
+// not by their line number, which changes every time content is added above
+// them.
+type Baseline struct {
+	Version int             `json:"version"`
+	Alerts  []BaselineEntry `json:"alerts"`
+
+	root   string
+	counts map[string]int
+}
+
+// A BaselineEntry is a single recorded Alert.
+type BaselineEntry struct {
+	Fingerprint string `json:"fingerprint"`
+	Path        string `json:"path"`
+	Check       string `json:"check"`
+	Match       string `json:"match"`
+}
+
+// NewBaseline creates a Baseline from the Alerts in linted.
+//
+// The Baseline is meant to be stored at path, which is used to make each
+// file's path relative (so that the Baseline can be shared).
+func NewBaseline(path string, linted []*File) *Baseline {
+	b := &Baseline{Version: baselineVersion, Alerts: []BaselineEntry{}}
+	b.init(path)
+
+	for _, f := range linted {
+		rel := b.relPath(f.Path)
+		for _, a := range f.SortedAlerts() {
+			b.Alerts = append(b.Alerts, BaselineEntry{
+				Fingerprint: fingerprint(rel, f, a),
+				Path:        rel,
+				Check:       a.Check,
+				Match:       a.Match,
+			})
+		}
+	}
+
+	sort.SliceStable(b.Alerts, func(i, j int) bool {
+		return b.Alerts[i].Path < b.Alerts[j].Path
+	})
+
+	return b
+}
+
+// ReadBaseline loads the Baseline stored at path.
+func ReadBaseline(path string) (*Baseline, error) {
+	b := &Baseline{}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return b, NewE100("ReadBaseline", err)
+	} else if err = json.Unmarshal(data, b); err != nil {
+		return b, NewE100("ReadBaseline", err)
+	}
+
+	b.init(path)
+	return b, nil
+}
+
+// Write saves b to path.
+func (b *Baseline) Write(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return NewE100("Baseline.Write", err)
+	}
+
+	err = ioutil.WriteFile(path, append(data, '\n'), 0644)
+	if err != nil {
+		return NewE100("Baseline.Write", err)
+	}
+
+	return nil
+}
+
+// Filter removes all of the recorded Alerts from linted, leaving only the
+// new ones.
+//
+// Each recorded Alert suppresses at most one occurrence, so introducing
+// another copy of an existing error is still reported.
+func (b *Baseline) Filter(linted []*File) {
+	remaining := make(map[string]int, len(b.counts))
+	for k, v := range b.counts {
+		remaining[k] = v
+	}
+
+	for _, f := range linted {
+		rel := b.relPath(f.Path)
+
+		alerts := []Alert{}
+		for _, a := range f.Alerts {
+			key := fingerprint(rel, f, a)
+			if remaining[key] > 0 {
+				remaining[key]--
+				continue
+			}
+			alerts = append(alerts, a)
+		}
+		f.Alerts = alerts
+	}
+}
+
+func (b *Baseline) init(path string) {
+	b.root = filepath.Dir(path)
+	if abs, err := filepath.Abs(b.root); err == nil {
+		b.root = abs
+	}
+
+	b.counts = make(map[string]int)
+	for _, entry := range b.Alerts {
+		b.counts[entry.Fingerprint]++
+	}
+}
+
+func (b *Baseline) relPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil && FileExists(path) {
+		if rel, err := filepath.Rel(b.root, abs); err == nil {
+			path = rel
+		}
+	}
+	return filepath.ToSlash(path)
+}
+
+func fingerprint(path string, f *File, a Alert) string {
+	context := ""
+	if a.Line > 0 && a.Line <= len(f.Lines) {
+		context = WhitespaceToSpace(strings.TrimSpace(f.Lines[a.Line-1]))
+	}
+
+	h := sha1.New()
+	for _, part := range []string{path, a.Check, a.Match, context} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
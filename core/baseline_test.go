@@ -0,0 +1,43 @@
+package core
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newBaselineFile(content string, alerts []Alert) *File {
+	f := &File{Path: "test.md", Content: content}
+	f.Lines = strings.SplitAfter(content, "\n")
+	f.Alerts = alerts
+	return f
+}
+
+func TestBaseline(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".vale-baseline.json")
+
+	old := newBaselineFile("This is very good.\n", []Alert{
+		{Check: "Test.Very", Line: 1, Span: []int{9, 12}, Match: "very"},
+	})
+	if err := NewBaseline(path, []*File{old}).Write(path); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := ReadBaseline(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The same line has moved down and another copy has been added.
+	cur := newBaselineFile("New line.\n\nThis is very good.\nThis is very bad.\n", []Alert{
+		{Check: "Test.Very", Line: 3, Span: []int{9, 12}, Match: "very"},
+		{Check: "Test.Very", Line: 4, Span: []int{9, 12}, Match: "very"},
+	})
+	b.Filter([]*File{cur})
+
+	if len(cur.Alerts) != 1 {
+		t.Fatalf("expected 1 alert, got %d", len(cur.Alerts))
+	} else if cur.Alerts[0].Line != 4 {
+		t.Errorf("expected the alert on line 4, got line %d", cur.Alerts[0].Line)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	"github.com/errata-ai/vale/v2/config"
@@ -86,24 +87,29 @@ func NewLinedBlock(ctx, txt, sel string, line int) Block {
 
 // A File represents a linted text file.
 type File struct {
-	Alerts     []Alert           // all alerts associated with this file
-	BaseStyles []string          // base style assigned in .vale
-	Checks     map[string]bool   // syntax-specific checks assigned in .vale
-	ChkToCtx   map[string]string // maps a temporary context to a particular check
-	Comments   map[string]bool   // comment control statements
-	Content    string            // the raw file contents
-	Counts     map[string]int    // word counts
-	Format     string            // 'code', 'markup' or 'prose'
-	Lines      []string          // the File's Content split into lines
-	Command    string            // a user-provided parsing CLI command
-	NormedExt  string            // the normalized extension (see util/format.go)
-	Path       string            // the full path
-	Transform  string            // XLST transform
-	RealExt    string            // actual file extension
-	Scanner    *bufio.Scanner    // used by lintXXX functions
-	Sequences  []string          // tracks various info (e.g., defined abbreviations)
-	Simple     bool              // indicates that we should ignore syntax (lint lint-by-line)
-	Summary    bytes.Buffer      // holds content to be included in summarization checks
+	Alerts     []Alert                             // all alerts associated with this file
+	BaseStyles []string                            // base style assigned in .vale
+	Checks     map[string]bool                     // syntax-specific checks assigned in .vale
+	ChkToCtx   map[string]string                   // maps a temporary context to a particular check
+	Comments   map[string]bool                     // comment control statements
+	Content    string                              // the raw file contents
+	Counts     map[string]int                      // word counts
+	Error      string                              // why the file couldn't be linted (if it couldn't)
+	Format     string                              // 'code', 'markup' or 'prose'
+	Levels     map[string]string                   // rule levels assigned in nested .vale.ini files
+	Lines      []string                            // the File's Content split into lines
+	Matched    func(check string, d time.Duration) // records time spent matching a check's pattern (profiling only)
+	Command    string                              // a user-provided parsing CLI command
+	Parser     string                              // the format of Command's output, or how to parse a native format
+	NormedExt  string                              // the normalized extension (see util/format.go)
+	Options    map[string]string                   // the section whose rule options apply to each rule
+	Path       string                              // the full path
+	Transform  string                              // XLST transform
+	RealExt    string                              // actual file extension
+	Scanner    *bufio.Scanner                      // used by lintXXX functions
+	Sequences  []string                            // tracks various info (e.g., defined abbreviations)
+	Simple     bool                                // indicates that we should ignore syntax (lint lint-by-line)
+	Summary    bytes.Buffer                        // holds content to be included in summarization checks
 
 	history  map[string]int
 	limits   map[string]int
@@ -193,27 +199,55 @@ func (a ByName) Less(i, j int) bool {
 	return ai.Path < aj.Path
 }
 
+// TimeMatch runs match -- a check's search for its pattern -- and reports how
+// long it took to `f.Matched`, if set.
+func (f *File) TimeMatch(check string, match func()) {
+	if f == nil || f.Matched == nil {
+		match()
+		return
+	}
+	start := time.Now()
+	match()
+	f.Matched(check, time.Since(start))
+}
+
 // NewFile initilizes a File.
 func NewFile(src string, config *config.Config) (*File, error) {
-	var scanner *bufio.Scanner
 	var format, ext string
 	var fbytes []byte
 
 	if FileExists(src) {
-		fbytes, _ = ioutil.ReadFile(src)
-		scanner = bufio.NewScanner(bytes.NewReader(fbytes))
+		var err error
+		if fbytes, err = ioutil.ReadFile(src); err != nil {
+			return &File{Path: src}, NewE100(src, err)
+		}
 		if config.InExt != ".txt" {
 			ext, format = FormatFromExt(config.InExt, config.Formats)
 		} else {
 			ext, format = FormatFromExt(src, config.Formats)
 		}
 	} else {
-		scanner = bufio.NewScanner(strings.NewReader(src))
 		ext, format = FormatFromExt(config.InExt, config.Formats)
 		fbytes = []byte(src)
 		src = "stdin" + config.InExt
 	}
 
+	return newFile(src, ext, format, fbytes, config)
+}
+
+// NewFileFromContent initializes a File whose content is given rather than
+// read from disk (e.g., the staged version of a file).
+//
+// The path, which doesn't need to exist, determines the File's format and
+// which of the configuration's sections apply to it.
+func NewFileFromContent(path, content string, config *config.Config) (*File, error) {
+	ext, format := FormatFromExt(path, config.Formats)
+	return newFile(path, ext, format, []byte(content), config)
+}
+
+func newFile(src, ext, format string, fbytes []byte, config *config.Config) (*File, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(fbytes))
+
 	fp := src
 	old := filepath.Ext(fp)
 	if normed, found := config.Formats[strings.Trim(old, ".")]; found {
@@ -236,11 +270,53 @@ func NewFile(src string, config *config.Config) (*File, error) {
 		}
 	}
 
+	// The first (sorted) matching section that overrides a rule's options
+	// takes precedence over the global ones.
+	options := make(map[string]string)
+	for name := range config.RuleOpts["*"] {
+		options[name] = "*"
+	}
+	sections := []string{}
+	for sec := range config.RuleOpts {
+		if pat, found := config.SecToPat[sec]; found && pat.Match(fp) {
+			sections = append(sections, sec)
+		}
+	}
+	sort.Strings(sections)
+	for _, sec := range sections {
+		for name := range config.RuleOpts[sec] {
+			if options[name] == "" || options[name] == "*" {
+				options[name] = sec
+			}
+		}
+	}
+
+	command, parser := "", ""
+	for sec, cmd := range config.Commands {
+		if pat, found := config.SecToPat[sec]; found && pat.Match(fp) {
+			command, parser = cmd, config.Parsers[sec]
+			if parser == "" {
+				parser = "html"
+			}
+			break
+		}
+	}
+	if command == "" {
+		// Without a Command, Parser selects how a format that we support
+		// natively is parsed (e.g., "rst2html" for reStructuredText).
+		for sec, p := range config.Parsers {
+			if pat, found := config.SecToPat[sec]; found && pat.Match(fp) {
+				parser = p
+				break
+			}
+		}
+	}
+
 	transform := ""
 	for sec, p := range config.Stylesheets {
 		pat, err := glob.Compile(sec)
 		if err != nil {
-			return &File{}, NewE100(src, err)
+			return &File{Path: src}, NewE100(src, err)
 		} else if pat.Match(src) {
 			transform = p
 			break
@@ -255,11 +331,21 @@ func NewFile(src string, config *config.Config) (*File, error) {
 		BaseStyles: baseStyles, Checks: checks, Scanner: scanner, Lines: lines,
 		Comments: make(map[string]bool), Content: content, history: make(map[string]int),
 		Simple: config.Simple, Transform: transform, limits: make(map[string]int),
+		Options: options, Command: command, Parser: parser,
 	}
 
 	return &file, nil
 }
 
+// Level returns the level of the rule name for this file, which defaults to
+// level unless it has been overridden by a nested configuration.
+func (f *File) Level(name, level string) string {
+	if override, found := f.Levels[name]; found {
+		return override
+	}
+	return level
+}
+
 // SortedAlerts returns all of f's alerts sorted by line and column.
 func (f *File) SortedAlerts() []Alert {
 	sort.Sort(ByPosition(f.Alerts))
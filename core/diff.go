@@ -0,0 +1,164 @@
+package core
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jdkato/regexp"
+)
+
+var hunkHeader = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// ChangedLines maps a file's path to its added (or modified) lines, as
+// described by a unified diff.
+type ChangedLines map[string]map[int]bool
+
+// ParseDiff reads the added and modified lines from a unified diff (such as
+// the output of `git diff`).
+//
+// Paths are returned as they appear in the diff, minus any `b/` prefix.
+// Deleted files aren't included.
+func ParseDiff(r io.Reader) (ChangedLines, error) {
+	changed := ChangedLines{}
+
+	var path string
+	var line, oldLeft, newLeft int
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1<<30)
+	for scanner.Scan() {
+		text := scanner.Text()
+
+		if oldLeft > 0 || newLeft > 0 {
+			// We're inside of a hunk.
+			switch {
+			case strings.HasPrefix(text, "+"):
+				if path != "" {
+					changed[path][line] = true
+				}
+				line++
+				newLeft--
+			case strings.HasPrefix(text, "-"):
+				oldLeft--
+			case strings.HasPrefix(text, `\`):
+				// "\ No newline at end of file"
+			default:
+				line++
+				oldLeft--
+				newLeft--
+			}
+			continue
+		}
+
+		if strings.HasPrefix(text, "+++ ") {
+			path = diffPath(text[4:])
+			if path != "" {
+				if _, found := changed[path]; !found {
+					changed[path] = map[int]bool{}
+				}
+			}
+		} else if m := hunkHeader.FindStringSubmatch(text); m != nil {
+			oldLeft = hunkSize(m[2])
+			line, _ = strconv.Atoi(m[3])
+			newLeft = hunkSize(m[4])
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return changed, NewE100("ParseDiff", err)
+	}
+
+	return changed, nil
+}
+
+// Paths returns the (sorted) paths of all files with at least one change.
+func (c ChangedLines) Paths() []string {
+	paths := []string{}
+	for path, lines := range c {
+		if len(lines) > 0 {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Resolve returns a copy of c whose paths, which are relative to dir (e.g.,
+// the root of a Git repository), are instead relative to the current
+// directory.
+func (c ChangedLines) Resolve(dir string) ChangedLines {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return c
+	}
+
+	resolved := ChangedLines{}
+	for path, lines := range c {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, filepath.FromSlash(path))
+		}
+		if rel, err := filepath.Rel(cwd, path); err == nil {
+			path = rel
+		}
+		resolved[path] = lines
+	}
+
+	return resolved
+}
+
+// Filter removes all alerts from linted that aren't on a changed line.
+func (c ChangedLines) Filter(linted []*File) {
+	byPath := map[string]map[int]bool{}
+	for path, lines := range c {
+		byPath[absPath(path)] = lines
+	}
+
+	for _, f := range linted {
+		lines := byPath[absPath(f.Path)]
+
+		alerts := []Alert{}
+		for _, a := range f.Alerts {
+			if lines[a.Line] {
+				alerts = append(alerts, a)
+			}
+		}
+		f.Alerts = alerts
+	}
+}
+
+func diffPath(s string) string {
+	if idx := strings.Index(s, "\t"); idx >= 0 {
+		// Some tools include a timestamp after the path.
+		s = s[:idx]
+	}
+
+	if s == "/dev/null" {
+		return ""
+	} else if unquoted, err := strconv.Unquote(s); err == nil {
+		// Git quotes paths that contain unusual characters.
+		s = unquoted
+	}
+
+	return strings.TrimPrefix(s, "b/")
+}
+
+func hunkSize(s string) int {
+	if s == "" {
+		return 1
+	}
+	// NOTE: `hunkHeader` ensures that this is a number.
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
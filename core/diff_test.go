@@ -0,0 +1,89 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testDiff = `diff --git a/docs/a.md b/docs/a.md
+index 3b18e51..a8c2f4e 100644
+--- a/docs/a.md
++++ b/docs/a.md
+@@ -1,4 +1,5 @@
+ # Title
+-This is old.
++This is new.
++--- this line starts with dashes
+ Some context.
+ More context.
+@@ -10,0 +12 @@ Heading
++Added at the end.
+diff --git a/old.md b/old.md
+deleted file mode 100644
+--- a/old.md
++++ /dev/null
+@@ -1 +0,0 @@
+-Gone.
+diff --git a/new.md b/new.md
+new file mode 100644
+--- /dev/null
++++ b/new.md
+@@ -0,0 +1,2 @@
++One.
++Two.
+\ No newline at end of file
+`
+
+func TestParseDiff(t *testing.T) {
+	changed, err := ParseDiff(strings.NewReader(testDiff))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := ChangedLines{
+		"docs/a.md": {2: true, 3: true, 12: true},
+		"new.md":    {1: true, 2: true},
+	}
+	if !reflect.DeepEqual(changed, expected) {
+		t.Errorf("expected = %v, got = %v", expected, changed)
+	}
+
+	paths := changed.Paths()
+	if !reflect.DeepEqual(paths, []string{"docs/a.md", "new.md"}) {
+		t.Errorf("unexpected paths: %v", paths)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A diff from the root of a repository, read in one of its
+	// subdirectories.
+	root := filepath.Dir(cwd)
+	changed := ChangedLines{
+		"docs/a.md":                        {1: true},
+		filepath.Base(cwd) + "/b.md":       {2: true},
+		filepath.Join(root, "abs", "c.md"): {3: true},
+	}
+
+	expected := ChangedLines{
+		filepath.Join("..", "docs", "a.md"): {1: true},
+		"b.md":                              {2: true},
+		filepath.Join("..", "abs", "c.md"):  {3: true},
+	}
+	if resolved := changed.Resolve(root); !reflect.DeepEqual(resolved, expected) {
+		t.Errorf("expected = %v, got = %v", expected, resolved)
+	}
+
+	f := &File{Path: "b.md", Alerts: []Alert{{Line: 1}, {Line: 2}}}
+	changed.Resolve(root).Filter([]*File{f})
+	if len(f.Alerts) != 1 || f.Alerts[0].Line != 2 {
+		t.Errorf("expected the alert on line 2, got %v", f.Alerts)
+	}
+}
@@ -161,3 +161,20 @@ func NewE201FromPosition(msg, file string, goal int) error {
 			return position == goal
 		})
 }
+
+// ErrorMessage returns a plain, one-line version of err for reports that
+// list the files that couldn't be linted.
+//
+// For errors created by `NewError`, this is the last line of the message
+// (i.e., without the code, title, or any highlighted configuration).
+func ErrorMessage(err error) string {
+	msg := strings.TrimSpace(StripANSI(err.Error()))
+
+	parts := strings.Split(msg, "\n\n")
+	if n := len(parts); n >= 3 && strings.HasPrefix(parts[n-1], "Execution stopped") {
+		lines := strings.Split(strings.TrimSpace(parts[n-2]), "\n")
+		msg = lines[len(lines)-1]
+	}
+
+	return strings.TrimSpace(WhitespaceToSpace(msg))
+}
@@ -0,0 +1,225 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/jdkato/regexp"
+)
+
+// A Fix is an in-line edit, computed from an Alert's Action, that resolves the
+// Alert.
+type Fix struct {
+	Alert Alert  // the Alert being resolved
+	Line  int    // the (1-based) line of the edit
+	Start int    // the (0-based) rune offset where the edit starts
+	End   int    // the (0-based) rune offset where the edit ends (exclusive)
+	Text  string // the replacement text
+}
+
+// FixAlert computes the replacement text for the given Alert's Match based on
+// its Action.
+//
+// The supported actions are `replace` (using the first suggestion), `remove`,
+// and `edit`.
+func FixAlert(a Alert) (string, error) {
+	params := a.Action.Params
+	switch a.Action.Name {
+	case "replace":
+		if len(params) == 0 || params[0] == "" {
+			return "", errors.New("no replacement provided")
+		}
+		return params[0], nil
+	case "remove":
+		return "", nil
+	case "edit":
+		return editMatch(a.Match, params)
+	default:
+		return "", fmt.Errorf("unsupported action '%s'", a.Action.Name)
+	}
+}
+
+func editMatch(match string, params []string) (string, error) {
+	if len(params) == 0 {
+		return "", errors.New("missing 'edit' parameters")
+	}
+
+	arg := func(i int, def string) string {
+		if len(params) > i {
+			return params[i]
+		}
+		return def
+	}
+
+	switch params[0] {
+	case "replace":
+		if len(params) < 3 {
+			return "", errors.New("'replace' requires a target and replacement")
+		}
+		return strings.Replace(match, params[1], params[2], -1), nil
+	case "regex":
+		if len(params) < 3 {
+			return "", errors.New("'regex' requires a pattern and replacement")
+		}
+		re, err := regexp.Compile(params[1])
+		if err != nil {
+			return "", err
+		}
+		return re.ReplaceAllString(match, params[2]), nil
+	case "trim":
+		return strings.Trim(match, arg(1, " ")), nil
+	case "trim_left":
+		return strings.TrimLeft(match, arg(1, " ")), nil
+	case "trim_right":
+		return strings.TrimRight(match, arg(1, " ")), nil
+	case "truncate":
+		return strings.Split(match, arg(1, " "))[0], nil
+	case "split":
+		parts := strings.Split(match, arg(1, " "))
+		idx, err := strconv.Atoi(arg(2, "0"))
+		if err != nil || idx < 0 || idx >= len(parts) {
+			return "", fmt.Errorf("invalid 'split' index '%s'", arg(2, "0"))
+		}
+		return parts[idx], nil
+	default:
+		return "", fmt.Errorf("unsupported edit '%s'", params[0])
+	}
+}
+
+// FindFixes computes the Fixes that can be applied to content for the given
+// Alerts.
+//
+// Alerts without a supported Action, or whose Match can't be found at their
+// reported location, are skipped. When two Fixes overlap, the one that starts
+// first wins; ties go to the longer edit and then to the check name.
+func FindFixes(content string, alerts []Alert) []Fix {
+	var candidates, fixes []Fix
+
+	lines := strings.SplitAfter(content, "\n")
+	for _, a := range alerts {
+		if a.Line < 1 || a.Line > len(lines) || len(a.Span) != 2 {
+			continue
+		} else if a.Match == "" || strings.Contains(a.Match, "\n") {
+			continue
+		}
+
+		text, err := FixAlert(a)
+		if err != nil || strings.Contains(text, "\n") || text == a.Match {
+			continue
+		}
+
+		line := []rune(strings.TrimRight(lines[a.Line-1], "\r\n"))
+		start := locateMatch(line, []rune(a.Match), a.Span[0]-1)
+		if start < 0 {
+			continue
+		}
+
+		end := start + len([]rune(a.Match))
+		if text == "" {
+			start, end = widenRemoval(line, start, end)
+		}
+
+		candidates = append(candidates, Fix{
+			Alert: a,
+			Line:  a.Line,
+			Start: start,
+			End:   end,
+			Text:  text,
+		})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		ci, cj := candidates[i], candidates[j]
+		if ci.Line != cj.Line {
+			return ci.Line < cj.Line
+		} else if ci.Start != cj.Start {
+			return ci.Start < cj.Start
+		} else if ci.End != cj.End {
+			return ci.End > cj.End
+		}
+		return ci.Alert.Check < cj.Alert.Check
+	})
+
+	for _, fix := range candidates {
+		if n := len(fixes); n > 0 {
+			last := fixes[n-1]
+			if last.Line == fix.Line && fix.Start < last.End {
+				// This edit overlaps one that we've already accepted.
+				continue
+			}
+		}
+		fixes = append(fixes, fix)
+	}
+
+	return fixes
+}
+
+// ApplyFixes returns content with the given Fixes applied.
+//
+// The Fixes are expected to be sorted and non-overlapping (see `FindFixes`).
+func ApplyFixes(content string, fixes []Fix) string {
+	lines := strings.SplitAfter(content, "\n")
+	for i := len(fixes) - 1; i >= 0; i-- {
+		fix := fixes[i]
+
+		line := lines[fix.Line-1]
+		body := strings.TrimRight(line, "\r\n")
+
+		runes := []rune(body)
+		edited := string(runes[:fix.Start]) + fix.Text + string(runes[fix.End:])
+
+		lines[fix.Line-1] = edited + line[len(body):]
+	}
+	return strings.Join(lines, "")
+}
+
+// widenRemoval extends the span, line[start:end], of a word that's being
+// removed so that we don't leave behind a double space, a space before
+// punctuation, or a dangling comma -- e.g., removing "very" from "It is very,
+// good." gives "It is good." rather than "It is, good.".
+func widenRemoval(line []rune, start, end int) (int, int) {
+	if end < len(line) && line[end] == ',' {
+		end++
+	}
+
+	if start > 0 && line[start-1] == ' ' {
+		atEnd := end == len(line) || unicode.IsPunct(line[end])
+		if atEnd || unicode.IsSpace(line[end]) {
+			start--
+		}
+		if atEnd && start > 0 && line[start-1] == ',' {
+			// The word ended a clause, so its comma goes too.
+			start--
+		}
+	} else if end < len(line) && line[end] == ' ' {
+		// The word starts the line (or follows an opening bracket, etc.).
+		end++
+	}
+
+	return start, end
+}
+
+// locateMatch finds the rune offset of match in line, preferring the
+// occurrence closest to the reported offset, hint.
+func locateMatch(line, match []rune, hint int) int {
+	best := -1
+	for i := 0; i+len(match) <= len(line); i++ {
+		if string(line[i:i+len(match)]) != string(match) {
+			continue
+		} else if best < 0 || abs(i-hint) < abs(best-hint) {
+			best = i
+		}
+	}
+	return best
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,96 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFixAlert(t *testing.T) {
+	cases := []struct {
+		action Action
+		match  string
+		fixed  string
+	}{
+		{Action{Name: "replace", Params: []string{"JavaScript", "JS"}}, "javascript", "JavaScript"},
+		{Action{Name: "remove"}, "very", ""},
+		{Action{Name: "edit", Params: []string{"replace", "'", ""}}, "dog's", "dogs"},
+		{Action{Name: "edit", Params: []string{"regex", `(\w+)-(\w+)`, "$1 $2"}}, "e-mail", "e mail"},
+		{Action{Name: "edit", Params: []string{"trim_right", "."}}, "etc..", "etc"},
+		{Action{Name: "edit", Params: []string{"truncate", " "}}, "very good", "very"},
+		{Action{Name: "edit", Params: []string{"split", "/", "1"}}, "and/or", "or"},
+	}
+	for _, tc := range cases {
+		fixed, err := FixAlert(Alert{Action: tc.action, Match: tc.match})
+		if err != nil {
+			t.Fatal(err)
+		} else if fixed != tc.fixed {
+			t.Errorf("expected = %q, got = %q", tc.fixed, fixed)
+		}
+	}
+
+	if _, err := FixAlert(Alert{Action: Action{Name: "suggest"}}); err == nil {
+		t.Error("expected an error for an unsupported action")
+	}
+}
+
+func TestApplyFixes(t *testing.T) {
+	content := "I love javascript on my web site.\r\nThis is very good.\n"
+	alerts := []Alert{
+		{
+			Check: "Test.Terms", Line: 1, Span: []int{8, 17}, Match: "javascript",
+			Action: Action{Name: "replace", Params: []string{"JavaScript"}},
+		},
+		{
+			Check: "Test.Terms", Line: 1, Span: []int{25, 32}, Match: "web site",
+			Action: Action{Name: "replace", Params: []string{"website"}},
+		},
+		{
+			// Overlaps with 'web site' above, so it should be skipped.
+			Check: "Test.Web", Line: 1, Span: []int{25, 27}, Match: "web",
+			Action: Action{Name: "replace", Params: []string{"Web"}},
+		},
+		{
+			Check: "Test.Very", Line: 2, Span: []int{9, 12}, Match: "very",
+			Action: Action{Name: "remove"},
+		},
+		{
+			// The match doesn't exist on this line.
+			Check: "Test.Missing", Line: 2, Span: []int{1, 3}, Match: "foo",
+			Action: Action{Name: "remove"},
+		},
+	}
+
+	fixes := FindFixes(content, alerts)
+	if len(fixes) != 3 {
+		t.Fatalf("expected 3 fixes, got %d", len(fixes))
+	}
+
+	expected := "I love JavaScript on my website.\r\nThis is good.\n"
+	if fixed := ApplyFixes(content, fixes); fixed != expected {
+		t.Errorf("expected = %q, got = %q", expected, fixed)
+	}
+}
+
+func TestFindFixesRemoval(t *testing.T) {
+	cases := []struct {
+		content  string
+		expected string
+	}{
+		{"It is very good.\n", "It is good.\n"},
+		{"It is very, good.\n", "It is good.\n"},
+		{"It is good, very.\n", "It is good.\n"},
+		{"Very good.\n", "good.\n"},
+	}
+	for _, tc := range cases {
+		start := strings.Index(strings.ToLower(tc.content), "very")
+		match := tc.content[start : start+4]
+		alerts := []Alert{{
+			Check: "Test.Very", Line: 1, Span: []int{start + 1, start + 4},
+			Match: match, Action: Action{Name: "remove"},
+		}}
+		fixed := ApplyFixes(tc.content, FindFixes(tc.content, alerts))
+		if fixed != tc.expected {
+			t.Errorf("expected = %q, got = %q", tc.expected, fixed)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package core
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jdkato/regexp"
+)
+
+// IgnoreFiles are the files, in order of precedence, that list paths to be
+// skipped while walking a directory. They both follow the `.gitignore`
+// format.
+var IgnoreFiles = []string{".gitignore", ".valeignore"}
+
+// An ignorePattern is a single (non-comment) line of an ignore file.
+type ignorePattern struct {
+	re      *regexp.Regexp
+	negate  bool // the pattern started with '!'
+	dirOnly bool // the pattern ended with '/'
+}
+
+// An Ignorer decides which paths in a directory tree should be skipped,
+// according to the ignore files found in (and above) the tree.
+//
+// Like Git, patterns are relative to the directory of the file that defines
+// them, files in subdirectories take precedence over those in their parents,
+// and the last matching pattern wins (which allows for negations).
+type Ignorer struct {
+	top  string
+	dirs map[string][]ignorePattern
+}
+
+// NewIgnorer creates an Ignorer for the directory tree rooted at root.
+//
+// We also consider the ignore files above root, up to the root of its Git
+// repository (if there is one).
+func NewIgnorer(root string) *Ignorer {
+	root = absPath(root)
+	if !IsDir(root) {
+		root = filepath.Dir(root)
+	}
+
+	top := root
+	for dir := root; ; dir = filepath.Dir(dir) {
+		if FileExists(filepath.Join(dir, ".git")) {
+			top = dir
+			break
+		} else if dir == filepath.Dir(dir) {
+			break
+		}
+	}
+
+	return &Ignorer{top: top, dirs: make(map[string][]ignorePattern)}
+}
+
+// Ignored determines if the given path should be skipped.
+func (ig *Ignorer) Ignored(path string, isDir bool) bool {
+	path = absPath(path)
+
+	// Collect the directories, from the top down, whose ignore files apply.
+	chain := []string{}
+	for dir := filepath.Dir(path); ; dir = filepath.Dir(dir) {
+		chain = append([]string{dir}, chain...)
+		if dir == ig.top || dir == filepath.Dir(dir) {
+			break
+		}
+	}
+	if chain[0] != ig.top {
+		// The path isn't inside of our tree.
+		return false
+	}
+
+	ignored := false
+	for _, dir := range chain {
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			continue
+		}
+		rel = filepath.ToSlash(rel)
+
+		for _, p := range ig.patterns(dir) {
+			if (!p.dirOnly || isDir) && p.re.MatchString(rel) {
+				ignored = !p.negate
+			}
+		}
+	}
+
+	return ignored
+}
+
+func (ig *Ignorer) patterns(dir string) []ignorePattern {
+	if patterns, found := ig.dirs[dir]; found {
+		return patterns
+	}
+
+	patterns := []ignorePattern{}
+	for _, name := range IgnoreFiles {
+		patterns = append(patterns, readIgnoreFile(filepath.Join(dir, name))...)
+	}
+
+	ig.dirs[dir] = patterns
+	return patterns
+}
+
+func readIgnoreFile(path string) []ignorePattern {
+	patterns := []ignorePattern{}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return patterns
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if p, ok := parseIgnorePattern(scanner.Text()); ok {
+			patterns = append(patterns, p)
+		}
+	}
+
+	return patterns
+}
+
+// parseIgnorePattern converts a line of an ignore file into a regular
+// expression that matches slash-separated paths relative to the file.
+func parseIgnorePattern(line string) (ignorePattern, bool) {
+	var p ignorePattern
+
+	line = strings.TrimRight(line, "\r")
+	if strings.HasSuffix(line, `\ `) {
+		line = strings.TrimRight(line[:len(line)-2], " ") + " "
+	} else {
+		line = strings.TrimRight(line, " ")
+	}
+
+	if line == "" || strings.HasPrefix(line, "#") {
+		return p, false
+	} else if strings.HasPrefix(line, "!") {
+		p.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\#`) || strings.HasPrefix(line, `\!`) {
+		line = line[1:]
+	}
+
+	if strings.HasSuffix(line, "/") {
+		p.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+
+	// A pattern without a slash (other than a trailing one) matches at any
+	// level below the ignore file.
+	prefix := "(?:.*/)?"
+	if strings.Contains(line, "/") {
+		prefix = ""
+		line = strings.TrimPrefix(line, "/")
+	}
+
+	if line == "" {
+		return p, false
+	}
+
+	re, err := regexp.Compile("^" + prefix + globToRegexp(line) + "$")
+	if err != nil {
+		return p, false
+	}
+	p.re = re
+
+	return p, true
+}
+
+func globToRegexp(glob string) string {
+	var sb strings.Builder
+
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case strings.HasPrefix(glob[i:], "**/") && (i == 0 || glob[i-1] == '/'):
+			// Zero or more directories.
+			sb.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**") && i+2 == len(glob) && i > 0 && glob[i-1] == '/':
+			// Everything inside of a directory.
+			sb.WriteString(".*")
+			i++
+		case c == '*':
+			sb.WriteString("[^/]*")
+		case c == '?':
+			sb.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				sb.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			sb.WriteString("[" + class + "]")
+			i += end + 1
+		case c == '\\' && i+1 < len(glob):
+			i++
+			sb.WriteString(regexp.QuoteMeta(string(glob[i])))
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	return sb.String()
+}
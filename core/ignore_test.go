@@ -0,0 +1,59 @@
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIgnorer(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		".git/HEAD":           "",
+		".gitignore":          "# Build output\nbuild/\n*.log\n/root-only.md\n!keep.log\n",
+		"docs/.valeignore":    "drafts/**\n**/generated/*.md\n",
+		"docs/sub/.gitignore": "!important.log\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			t.Fatal(err)
+		} else if err = ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases := []struct {
+		path    string
+		isDir   bool
+		ignored bool
+	}{
+		{"build", true, true},
+		{"docs/build", true, true},
+		{"build", false, false}, // a file named "build"
+		{"error.log", false, true},
+		{"docs/error.log", false, true},
+		{"keep.log", false, false},
+		{"docs/sub/important.log", false, false},
+		{"docs/important.log", false, true},
+		{"root-only.md", false, true},
+		{"docs/root-only.md", false, false},
+		{"docs/drafts/a.md", false, true},
+		{"drafts/a.md", false, false},
+		{"docs/x/generated/a.md", false, true},
+		{"docs/generated/a.md", false, true},
+		{"docs/generated/a.txt", false, false},
+		{"docs/a.md", false, false},
+	}
+
+	// Walking a subdirectory still applies the ignore files above it.
+	ig := NewIgnorer(filepath.Join(dir, "docs"))
+	for _, c := range cases {
+		path := filepath.Join(dir, filepath.FromSlash(c.path))
+		if ignored := ig.Ignored(path, c.isDir); ignored != c.ignored {
+			t.Errorf("%s (dir = %v): expected %v, got %v", c.path, c.isDir, c.ignored, ignored)
+		}
+	}
+}
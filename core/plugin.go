@@ -0,0 +1,71 @@
+package core
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// A PluginRequest is sent from Vale to a plugin process.
+//
+// Plugins are stand-alone executables stored in the `plugins` directory on
+// `StylesPath`. Vale starts each plugin once and then communicates with it
+// over stdin/stdout, with one JSON-encoded message per line. The `describe`
+// method asks for the plugin's Scope and Level while the `run` method asks
+// for the Alerts found in `Text`.
+type PluginRequest struct {
+	Method string // "describe" or "run"
+	Text   string // the content of the plugin's scope
+	Path   string // the path of the file being linted
+}
+
+// A PluginResponse is sent from a plugin process to Vale in reply to a
+// PluginRequest.
+type PluginResponse struct {
+	Scope  string  // the scope the plugin applies to (describe)
+	Level  string  // the plugin's default level (describe)
+	Alerts []Alert // the Alerts found in the given text (run)
+	Error  string  // a description of any failure
+}
+
+// ServePlugin answers Vale's requests on stdin/stdout using the given Plugin.
+//
+// This allows a Go-based Plugin to be built as a stand-alone executable:
+//
+//    func main() {
+//        core.ServePlugin(Example())
+//    }
+func ServePlugin(p Plugin) error {
+	return servePlugin(p, os.Stdin, os.Stdout)
+}
+
+func servePlugin(p Plugin, in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 1<<30)
+
+	enc := json.NewEncoder(out)
+	for scanner.Scan() {
+		var req PluginRequest
+		var resp PluginResponse
+
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			resp.Error = err.Error()
+		} else if req.Method == "describe" {
+			resp.Scope = p.Scope
+			resp.Level = p.Level
+		} else if req.Method == "run" {
+			file := File{Path: req.Path, RealExt: filepath.Ext(req.Path)}
+			resp.Alerts = p.Rule(req.Text, &file)
+		} else {
+			resp.Error = "unknown method '" + req.Method + "'"
+		}
+
+		if err := enc.Encode(resp); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
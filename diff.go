@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/errata-ai/vale/v2/core"
+)
+
+// readDiff loads the changed lines for `--diff`.
+//
+// The value is either a git revision to diff the working tree against or "-",
+// which means that a unified diff should be read from stdin.
+func readDiff(rev string) (core.ChangedLines, error) {
+	if rev == "-" {
+		changed, err := core.ParseDiff(os.Stdin)
+		if err != nil {
+			return changed, err
+		}
+
+		// NOTE: The paths in a diff from Git are relative to the root of the
+		// repository, not the current directory. Outside of a repository, we
+		// have nothing better to go on than the current directory.
+		if root, err := gitRoot("--diff"); err == nil {
+			changed = changed.Resolve(root)
+		}
+		return changed, nil
+	}
+
+	var stderr bytes.Buffer
+
+	cmd := exec.Command(
+		"git", "diff", "--relative", "--unified=0", "--no-color",
+		"--no-ext-diff", rev, "--")
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			err = errors.New(msg)
+		}
+		return nil, core.NewE100("--diff", err)
+	}
+
+	return core.ParseDiff(bytes.NewReader(out))
+}
+
+// existing returns the paths that exist on disk.
+func existing(paths []string) []string {
+	found := []string{}
+	for _, path := range paths {
+		if core.FileExists(path) {
+			found = append(found, path)
+		}
+	}
+	return found
+}
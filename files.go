@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/errata-ai/vale/v2/core"
+)
+
+// readFileList loads the paths given by `--files-from`.
+//
+// The value is either a file or "-", which means that the list should be read
+// from stdin. Paths are separated by newlines or, if the list contains any
+// NUL characters (e.g., from `git diff -z` or `find -print0`), by NULs.
+//
+// Paths that don't exist are skipped, since lists like these often include
+// deleted files (e.g., `git diff --name-only`).
+func readFileList(src string) ([]string, error) {
+	var data []byte
+	var err error
+
+	if src == "-" {
+		data, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		data, err = ioutil.ReadFile(src)
+	}
+	if err != nil {
+		return nil, core.NewE100("--files-from", err)
+	}
+
+	sep := []byte("\n")
+	if bytes.IndexByte(data, 0) >= 0 {
+		sep = []byte{0}
+	}
+
+	paths := []string{}
+	for _, entry := range bytes.Split(data, sep) {
+		path := strings.TrimRight(string(entry), "\r")
+		if path == "" || !core.FileExists(path) {
+			continue
+		} else if core.IsDir(path) {
+			return nil, core.NewE100(
+				"--files-from",
+				fmt.Errorf("'%s' is not a file", path))
+		}
+		paths = append(paths, path)
+	}
+
+	return paths, nil
+}
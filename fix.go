@@ -0,0 +1,69 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/errata-ai/vale/v2/config"
+	"github.com/errata-ai/vale/v2/core"
+	"github.com/errata-ai/vale/v2/ui"
+)
+
+// fixFiles applies the suggested fixes for each of the linted files.
+//
+// In `--dry-run` mode, the fixes are printed as a unified diff instead of
+// being written to disk. Otherwise, the fixed alerts are removed from their
+// file so that only the remaining ones are reported.
+func fixFiles(linted []*core.File, cfg *config.Config) error {
+	for _, f := range linted {
+		if len(f.Alerts) == 0 || !core.FileExists(f.Path) {
+			// There's nothing to fix or we're linting stdin.
+			continue
+		}
+
+		info, err := os.Stat(f.Path)
+		if err != nil {
+			return core.NewE100("fixFiles", err)
+		}
+
+		content, err := ioutil.ReadFile(f.Path)
+		if err != nil {
+			return core.NewE100("fixFiles", err)
+		}
+
+		fixes := core.FindFixes(string(content), f.Alerts)
+		if len(fixes) == 0 {
+			continue
+		}
+		fixed := core.ApplyFixes(string(content), fixes)
+
+		if cfg.DryRun {
+			ui.PrintDiff(os.Stdout, f.Path, string(content), fixed)
+			continue
+		}
+
+		err = ioutil.WriteFile(f.Path, []byte(fixed), info.Mode())
+		if err != nil {
+			return core.NewE100("fixFiles", err)
+		}
+		f.Alerts = unfixed(f.Alerts, fixes)
+	}
+	return nil
+}
+
+func unfixed(alerts []core.Alert, fixes []core.Fix) []core.Alert {
+	remaining := []core.Alert{}
+	for _, a := range alerts {
+		found := false
+		for _, fix := range fixes {
+			if fix.Alert.Check == a.Check && fix.Line == a.Line && fix.Alert.Span[0] == a.Span[0] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			remaining = append(remaining, a)
+		}
+	}
+	return remaining
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/errata-ai/vale/v2/core"
+	"github.com/errata-ai/vale/v2/lint"
+)
+
+// lintStaged lints the versions of the files that are staged in Git's index
+// (rather than those in the working tree) for `--staged`.
+//
+// This is what a pre-commit hook wants: a partially-staged file is linted as
+// it will be committed.
+//
+// As with `--rev`, files are matched against sections (and reported) by their
+// paths relative to the root of the repository (see `lintBlobs`).
+func lintStaged(l *lint.Linter, glob string) ([]*core.File, error) {
+	root, err := gitRoot("--staged")
+	if err != nil {
+		return nil, err
+	}
+
+	// NOTE: `--name-only` paths are always relative to the root of the
+	// repository, regardless of the current directory.
+	out, err := git(
+		"--staged", "-C", root, "diff", "--cached", "--name-only", "-z",
+		"--diff-filter=ACMR", "--no-renames")
+	if err != nil {
+		return nil, err
+	}
+
+	staged := []string{}
+	for _, rel := range strings.Split(out, "\x00") {
+		if rel != "" {
+			staged = append(staged, rel)
+		}
+	}
+
+	// `:<path>` names the blob stored in the index (stage 0).
+	return lintBlobs(l, glob, "--staged", root, ":", staged)
+}
+
+// lintRev lints the files as they exist at the given revision for `--rev`,
+// without touching the working tree.
+//
+// The configuration (including any nested configuration files) is read from
+// the working tree.
+func lintRev(l *lint.Linter, glob, rev string) ([]*core.File, error) {
+	root, err := gitRoot("--rev")
+	if err != nil {
+		return nil, err
+	}
+
+	commit, err := git("--rev", "-C", root, "rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	if err != nil {
+		return nil, core.NewE100(
+			"--rev",
+			fmt.Errorf("'%s' is not a valid commit", rev))
+	}
+	commit = strings.TrimSpace(commit)
+
+	out, err := git("--rev", "-C", root, "ls-tree", "-r", "-z", "--full-tree", commit)
+	if err != nil {
+		return nil, err
+	}
+
+	// Each entry is "<mode> <type> <object>\t<path>"; we skip submodules
+	// (type "commit") and symbolic links (mode 120000).
+	files := []string{}
+	for _, entry := range strings.Split(out, "\x00") {
+		parts := strings.SplitN(entry, "\t", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		fields := strings.Fields(parts[0])
+		if len(fields) == 3 && fields[1] == "blob" && fields[0] != "120000" {
+			files = append(files, parts[1])
+		}
+	}
+
+	return lintBlobs(l, glob, "--rev", root, commit+":", files)
+}
+
+// lintBlobs lints the Git objects named `prefix + path` for each of the given
+// paths (relative to root) that matches glob.
+//
+// Each file is linted as if it were stored at its path in the working tree and
+// we were at the root of the repository: its repository-relative path, which
+// is also the path that we report, determines its format and the sections that
+// apply to it.
+func lintBlobs(l *lint.Linter, glob, flag, root, prefix string, files []string) ([]*core.File, error) {
+	gp, err := core.NewGlob(glob)
+	if err != nil {
+		return nil, err
+	}
+
+	matched := []string{}
+	for _, rel := range files {
+		if gp.Match(rel) {
+			matched = append(matched, filepath.FromSlash(rel))
+		}
+	}
+
+	blobs, err := newBlobReader(flag, root)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the files that aren't skipped (e.g., because of their format) are
+	// read, one at a time, as they're linted.
+	linted, err := l.LintContentFrom(root, matched, func(i int) (string, error) {
+		return blobs.read(prefix + filepath.ToSlash(matched[i]))
+	})
+
+	if cerr := blobs.close(); err == nil {
+		err = cerr
+	}
+	return linted, err
+}
+
+// A blobReader reads objects from a Git repository using a single
+// `git cat-file --batch` process.
+//
+// It's safe for concurrent use, although the objects are read one at a time.
+type blobReader struct {
+	sync.Mutex
+
+	flag   string
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Reader
+	stderr bytes.Buffer
+}
+
+func newBlobReader(flag, root string) (*blobReader, error) {
+	b := &blobReader{flag: flag, cmd: exec.Command("git", "-C", root, "cat-file", "--batch")}
+	b.cmd.Stderr = &b.stderr
+
+	stdin, err := b.cmd.StdinPipe()
+	if err != nil {
+		return nil, core.NewE100(flag, err)
+	}
+
+	stdout, err := b.cmd.StdoutPipe()
+	if err != nil {
+		return nil, core.NewE100(flag, err)
+	}
+
+	if err = b.cmd.Start(); err != nil {
+		return nil, core.NewE100(flag, err)
+	}
+
+	b.stdin = stdin
+	b.stdout = bufio.NewReader(stdout)
+
+	return b, nil
+}
+
+// read returns the content of the named object.
+func (b *blobReader) read(name string) (string, error) {
+	b.Lock()
+	defer b.Unlock()
+
+	if _, err := io.WriteString(b.stdin, name+"\n"); err != nil {
+		return "", core.NewE100(b.flag, err)
+	}
+
+	// Each object is reported as "<sha> <type> <size>\n<content>\n" (or, if
+	// it doesn't exist, "<name> missing\n").
+	header, err := b.stdout.ReadString('\n')
+	if err != nil {
+		return "", core.NewE100(b.flag, err)
+	}
+
+	fields := strings.Fields(header)
+	if len(fields) != 3 || fields[1] != "blob" {
+		return "", core.NewE100(
+			b.flag,
+			fmt.Errorf("unable to read '%s'", name))
+	}
+
+	size, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return "", core.NewE100(b.flag, err)
+	}
+
+	blob := make([]byte, size+1)
+	if _, err = io.ReadFull(b.stdout, blob); err != nil {
+		return "", core.NewE100(b.flag, err)
+	}
+
+	return string(blob[:size]), nil
+}
+
+// close stops the `git cat-file` process.
+func (b *blobReader) close() error {
+	b.stdin.Close()
+	if err := b.cmd.Wait(); err != nil {
+		if msg := strings.TrimSpace(b.stderr.String()); msg != "" {
+			err = errors.New(msg)
+		}
+		return core.NewE100(b.flag, err)
+	}
+	return nil
+}
+
+// gitRoot returns the root of the current Git repository.
+func gitRoot(flag string) (string, error) {
+	root, err := git(flag, "rev-parse", "--show-toplevel")
+	return strings.TrimSpace(root), err
+}
+
+// git runs a git command, returning its output.
+//
+// flag is the option that the command is being run for, which is used to
+// report errors.
+func git(flag string, args ...string) (string, error) {
+	var stderr bytes.Buffer
+
+	cmd := exec.Command("git", args...)
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			err = errors.New(msg)
+		}
+		return "", core.NewE100(flag, err)
+	}
+
+	return string(out), nil
+}
@@ -3,6 +3,7 @@ module github.com/errata-ai/vale/v2
 go 1.15
 
 require (
+	github.com/BurntSushi/toml v0.4.1
 	github.com/Masterminds/sprig/v3 v3.1.0
 	github.com/gobwas/glob v0.2.3
 	github.com/jdkato/prose v1.2.1
@@ -0,0 +1,1361 @@
+package lint
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/errata-ai/vale/v2/core"
+	"github.com/jdkato/regexp"
+)
+
+// Our AsciiDoc parser covers the parts of the language that matter for
+// prose: sections, paragraphs, lists, admonitions, delimited blocks, tables,
+// attributes, includes, etc.
+//
+// See https://docs.asciidoctor.org/asciidoc/latest/.
+
+var reADocAttrEntry = regexp.MustCompile(`^:(!?\w[\w-]*!?):(?:[ \t]+(.*))?$`)
+var reADocAttrRef = regexp.MustCompile(`\{(\w[\w-]*)\}`)
+var reADocInclude = regexp.MustCompile(`^include::([^\s\[](?:[^\[]*[^\s\[])?)\[(.*)\]$`)
+var reADocConditional = regexp.MustCompile(`^(ifdef|ifndef|ifeval|endif)::(\S*?)\[(.*)\]$`)
+var reADocSection = regexp.MustCompile(`^(={1,6}|#{1,6})[ \t]+(\S.*)$`)
+var reADocSectionEnd = regexp.MustCompile(`[ \t]+(?:=+|#+)$`)
+var reADocBlockAttrs = regexp.MustCompile(`^\[(?:|[\w.#%{,"'\[].*)\]$`)
+var reADocBlockTitle = regexp.MustCompile(`^\.\.?[^ \t.]`)
+var reADocBlockMacro = regexp.MustCompile(`^(\w[\w-]*)::(\S*?)\[(.*)\]$`)
+var reADocBreak = regexp.MustCompile(`^(?:'''|<<<|---|\*\*\*|- - -|\* \* \*)$`)
+var reADocUnordered = regexp.MustCompile(`^[ \t]*(?:-|\*{1,5}|•)[ \t]+(?:\[[ x*]\][ \t]+)?`)
+var reADocOrdered = regexp.MustCompile(`^[ \t]*(?:\.{1,5}|\d+\.|[a-zA-Z]\.|[IVXivx]+\))[ \t]+`)
+var reADocCallout = regexp.MustCompile(`^<(?:\d+|\.)>[ \t]+`)
+var reADocTerm = regexp.MustCompile(`^[ \t]*([^ \t].*?)(?::::{0,2}|;;)(?:[ \t]+|$)`)
+var reADocAdmonition = regexp.MustCompile(`^(?:NOTE|TIP|IMPORTANT|WARNING|CAUTION):[ \t]+`)
+var reADocTable = regexp.MustCompile(`^[|!,:]={3,}$`)
+var reADocCellSpec = regexp.MustCompile(`^(?:(\d+)\*|(\d+)?(?:\.\d+)?\+)?[<^>]?(?:\.[<^>])?([aehlmdsv])?$`)
+var reADocComment = regexp.MustCompile(`<!--\s*(.*?)\s*-->`)
+var reADocPassComment = regexp.MustCompile(`^(?:pass:\[|\+\+\+)<!--\s*(.*?)\s*-->(?:\]|\+\+\+)$`)
+
+// adocIntrinsics are the attributes that are always defined.
+var adocIntrinsics = map[string]string{
+	"blank":          "",
+	"empty":          "",
+	"sp":             " ",
+	"nbsp":           " ",
+	"zwsp":           "​",
+	"wj":             "⁠",
+	"apos":           "'",
+	"quot":           "\"",
+	"lsquo":          "‘",
+	"rsquo":          "’",
+	"ldquo":          "“",
+	"rdquo":          "”",
+	"deg":            "°",
+	"plus":           "+",
+	"brvbar":         "¦",
+	"vbar":           "|",
+	"amp":            "&",
+	"lt":             "<",
+	"gt":             ">",
+	"startsb":        "[",
+	"endsb":          "]",
+	"caret":          "^",
+	"asterisk":       "*",
+	"tilde":          "~",
+	"backslash":      "\\",
+	"backtick":       "`",
+	"two-colons":     "::",
+	"two-semicolons": ";;",
+	"cpp":            "C++",
+	"pp":             "++",
+}
+
+// adocMaxDepth is the maximum depth of nested includes (which matches
+// Asciidoctor's default).
+const adocMaxDepth = 64
+
+// adocMaxInclude is the size, in bytes, of the largest file that we'll
+// include.
+const adocMaxInclude = 1 << 20
+
+// An adocParser splits an AsciiDoc document into srcBlocks.
+type adocParser struct {
+	attrs  map[string]string // the document's attributes
+	blocks []srcBlock
+
+	root    string          // the directory that includes are restricted to
+	visited map[string]bool // the files that we've already included
+}
+
+// adocMeta is the metadata -- i.e., block attributes and a title -- that
+// may precede a block.
+type adocMeta struct {
+	style   string
+	named   map[string]string
+	options []string
+	title   []srcLine
+}
+
+// parseADoc splits src, the content of the file at path, into the blocks
+// that we lint.
+//
+// Like Asciidoctor's safe mode, we only resolve includes of files in path's
+// directory (or its subdirectories); if path is empty, src doesn't have a
+// directory of its own and its includes are ignored.
+//
+// It also returns the inlineParser for the blocks' text, which substitutes
+// the document's attributes.
+func parseADoc(src, path string) ([]srcBlock, inlineParser) {
+	p := adocParser{attrs: map[string]string{}, visited: map[string]bool{}}
+
+	dir := ""
+	if path != "" {
+		dir = filepath.Dir(path)
+		if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+			p.root = resolved
+			p.visited[filepath.Join(resolved, filepath.Base(path))] = true
+		}
+	}
+	lines := p.preprocess(toLines(src), dir, 0)
+
+	i := 0
+	for i < len(lines) && (lines[i].isBlank() || isADocComment(lines[i].text)) {
+		i++
+	}
+	if i < len(lines) && strings.HasPrefix(lines[i].text, "= ") {
+		// The document's header: its title, which may be followed by author
+		// and revision lines.
+		p.body(lines[:i+1], nil)
+		for i++; i < len(lines) && !lines[i].isBlank(); i++ {
+		}
+	} else {
+		i = 0
+	}
+	p.body(lines[i:], nil)
+
+	return p.blocks, func(raw string, ignored []string) inlineText {
+		in := adocInline{
+			raw:     raw,
+			ignored: ignored,
+			lookup:  p.lookup,
+			masked:  make([]bool, len(raw)),
+			closers: map[int]int{},
+		}
+		in.parse()
+		return inlineText{text: in.sb.String(), masked: in.masked, spans: in.spans}
+	}
+}
+
+// preprocess applies the preprocessor directives (includes and conditionals)
+// and attribute entries in lines, returning the lines that remain.
+//
+// We don't lint the content of included files, which are linted on their
+// own, but we do keep track of the attributes that they define.
+func (p *adocParser) preprocess(lines []srcLine, dir string, depth int) []srcLine {
+	kept := []srcLine{}
+
+	// Whether each of the conditionals we're in is satisfied.
+	active := []bool{}
+	skipping := func() bool {
+		for _, ok := range active {
+			if !ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	for i := 0; i < len(lines); i++ {
+		ln := lines[i]
+
+		if m := reADocConditional.FindStringSubmatchIndex(ln.text); m != nil {
+			directive, target := ln.text[m[2]:m[3]], ln.text[m[4]:m[5]]
+			if directive == "endif" {
+				if len(active) > 0 {
+					active = active[:len(active)-1]
+				}
+				continue
+			}
+			ok := !skipping() && p.evaluate(directive, target)
+			if m[6] == m[7] || directive == "ifeval" {
+				// The brackets of an `ifeval` hold its expression.
+				active = append(active, ok)
+			} else if ok {
+				// A single-line conditional -- e.g., `ifdef::env-github[Text]`.
+				kept = append(kept, srcLine{num: ln.num, off: ln.off + m[6], text: ln.text[m[6]:m[7]]})
+			}
+			continue
+		} else if skipping() {
+			continue
+		}
+
+		if m := reADocAttrEntry.FindStringSubmatch(ln.text); m != nil {
+			value := m[2]
+			for strings.HasSuffix(value, " \\") && i+1 < len(lines) {
+				i++
+				value = strings.TrimSuffix(value, "\\") + strings.TrimSpace(lines[i].text)
+			}
+			p.define(m[1], value)
+			continue
+		} else if m := reADocInclude.FindStringSubmatch(ln.text); m != nil {
+			p.include(m[1], dir, depth)
+			continue
+		}
+
+		kept = append(kept, ln)
+	}
+
+	return kept
+}
+
+// include reads the attributes defined by an included file.
+//
+// Each file is only included once, which also means that an include cycle
+// can't recurse. We skip any files that are missing (which Asciidoctor would
+// warn about, but it's not our concern), outside of `root`, or larger than
+// `adocMaxInclude`.
+func (p *adocParser) include(target, dir string, depth int) {
+	path := p.substitute(target)
+	if p.root == "" || depth >= adocMaxDepth || strings.Contains(path, "://") {
+		return
+	} else if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil || p.visited[resolved] || !isWithin(p.root, resolved) {
+		return
+	}
+	p.visited[resolved] = true
+
+	fi, err := os.Stat(resolved)
+	if err != nil || !fi.Mode().IsRegular() || fi.Size() > adocMaxInclude {
+		return
+	}
+
+	content, err := ioutil.ReadFile(resolved)
+	if err != nil {
+		return
+	}
+	p.preprocess(toLines(core.Sanitize(string(content))), filepath.Dir(path), depth+1)
+}
+
+// isWithin determines if path is in dir (or one of its subdirectories).
+func isWithin(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// define sets (or, given a name like "name!", unsets) an attribute.
+func (p *adocParser) define(name, value string) {
+	if strings.HasPrefix(name, "!") || strings.HasSuffix(name, "!") {
+		delete(p.attrs, strings.ToLower(strings.Trim(name, "!")))
+		return
+	}
+	p.attrs[strings.ToLower(name)] = p.substitute(value)
+}
+
+func (p *adocParser) lookup(name string) (string, bool) {
+	name = strings.ToLower(name)
+	if value, found := p.attrs[name]; found {
+		return value, true
+	}
+	value, found := adocIntrinsics[name]
+	return value, found
+}
+
+// substitute replaces the attribute references in s with their values,
+// leaving any undefined references as they are.
+func (p *adocParser) substitute(s string) string {
+	return reADocAttrRef.ReplaceAllStringFunc(s, func(m string) string {
+		if value, found := p.lookup(m[1 : len(m)-1]); found {
+			return value
+		}
+		return m
+	})
+}
+
+// evaluate determines if a conditional directive is satisfied.
+func (p *adocParser) evaluate(directive, target string) bool {
+	if directive == "ifeval" {
+		// We can't evaluate expressions, so we lint their content.
+		return true
+	}
+
+	defined := false
+	if strings.Contains(target, "+") {
+		defined = true
+		for _, name := range strings.Split(target, "+") {
+			_, found := p.attrs[strings.ToLower(name)]
+			defined = defined && found
+		}
+	} else {
+		for _, name := range strings.Split(target, ",") {
+			_, found := p.attrs[strings.ToLower(name)]
+			defined = defined || found
+		}
+	}
+
+	return defined == (directive == "ifdef")
+}
+
+func (p *adocParser) add(kind blockKind, tags []string, lines []srcLine) {
+	for _, ln := range lines {
+		if !ln.isBlank() {
+			p.blocks = append(p.blocks, srcBlock{kind: kind, tags: tags, lines: lines})
+			return
+		}
+	}
+}
+
+// paragraphs adds each of the (blank-line-separated) paragraphs in lines.
+func (p *adocParser) paragraphs(lines []srcLine, tags []string) {
+	start := 0
+	for i := 0; i <= len(lines); i++ {
+		if i == len(lines) || lines[i].isBlank() {
+			if i > start {
+				p.add(textBlock, tags, lines[start:i])
+			}
+			start = i + 1
+		}
+	}
+}
+
+// body parses a sequence of blocks.
+func (p *adocParser) body(lines []srcLine, tags []string) {
+	meta := adocMeta{}
+	for i := 0; i < len(lines); {
+		i = p.element(lines, i, tags, &meta)
+	}
+}
+
+// element parses the block (or block metadata) starting at lines[i],
+// returning the index of the line after it.
+func (p *adocParser) element(lines []srcLine, i int, tags []string, meta *adocMeta) int {
+	ln := lines[i]
+	text := ln.text
+
+	switch {
+	case ln.isBlank():
+		return i + 1
+	case isADocDelimiter(text):
+		break
+	case isADocComment(text):
+		p.blocks = append(p.blocks, srcBlock{kind: commentBlock, lines: []srcLine{ln.cut(2)}})
+		return i + 1
+	case reADocBlockAttrs.MatchString(text):
+		meta.parse(text[1 : len(text)-1])
+		return i + 1
+	case reADocBlockTitle.MatchString(text):
+		meta.title = []srcLine{ln.cut(1)}
+		return i + 1
+	}
+
+	// The block's title is rendered before it.
+	p.add(textBlock, withTags(tags, "div"), meta.title)
+	block := *meta
+	*meta = adocMeta{}
+
+	if isADocDelimiter(text) {
+		return p.delimited(lines, i, tags, block)
+	} else if m := reADocSection.FindStringSubmatchIndex(text); m != nil {
+		title := ln.cut(m[4])
+		if end := reADocSectionEnd.FindStringIndex(title.text); end != nil {
+			title.text = title.text[:end[0]]
+		}
+		p.add(textBlock, withTags(tags, fmt.Sprintf("h%d", m[3]-m[2])), []srcLine{title})
+		return i + 1
+	} else if reADocBreak.MatchString(text) {
+		return i + 1
+	} else if m := reADocBlockMacro.FindStringSubmatchIndex(text); m != nil {
+		if text[m[2]:m[3]] == "image" {
+			if start, end, found := adocAlt(text, m[6], m[7]); found {
+				alt := srcLine{num: ln.num, off: ln.off + start, text: text[start:end]}
+				p.blocks = append(p.blocks, srcBlock{
+					kind: attrBlock, attr: "alt", tags: tags, lines: []srcLine{alt}})
+			}
+		}
+		return i + 1
+	} else if strings.HasPrefix(text, ">") {
+		// A Markdown-style block quote.
+		quote := []srcLine{}
+		for ; i < len(lines) && strings.HasPrefix(lines[i].text, ">"); i++ {
+			quoted := lines[i].cut(1)
+			if strings.HasPrefix(quoted.text, " ") {
+				quoted = quoted.cut(1)
+			}
+			quote = append(quote, quoted)
+		}
+		p.body(quote, withTags(tags, "blockquote"))
+		return i
+	}
+
+	if !isADocVerbatim(block.style) && !isADocRaw(block.style) {
+		if m := reADocUnordered.FindStringIndex(text); m != nil && m[1] < len(text) {
+			return p.item(lines, i, m[1], withTags(tags, "ul", "li"))
+		} else if m := reADocOrdered.FindStringIndex(text); m != nil && m[1] < len(text) {
+			return p.item(lines, i, m[1], withTags(tags, "ol", "li"))
+		} else if m := reADocCallout.FindStringIndex(text); m != nil && m[1] < len(text) {
+			return p.item(lines, i, m[1], withTags(tags, "ol", "li"))
+		} else if m := reADocTerm.FindStringSubmatchIndex(text); m != nil {
+			return p.term(lines, i, m, tags)
+		} else if ln.indent() > 0 {
+			// A literal paragraph.
+			end := i
+			for end < len(lines) && !lines[end].isBlank() {
+				end++
+			}
+			p.add(textBlock, withTags(tags, "pre"), lines[i:end])
+			return end
+		}
+	}
+
+	return p.paragraph(lines, i, tags, block.style)
+}
+
+// paragraph parses the paragraph starting at lines[i], which has the given
+// style (e.g., "source" or "NOTE").
+func (p *adocParser) paragraph(lines []srcLine, i int, tags []string, style string) int {
+	end, para := adocParagraph(lines, i, false)
+
+	switch {
+	case isADocVerbatim(style):
+		p.add(textBlock, withTags(tags, "pre"), para)
+	case isADocRaw(style):
+		p.comments(para)
+	case style == "quote" || style == "verse":
+		p.add(textBlock, withTags(tags, "blockquote"), para)
+	case isADocAdmonition(style):
+		p.add(textBlock, withTags(tags, "div"), para)
+	default:
+		if m := reADocAdmonition.FindStringIndex(para[0].text); m != nil {
+			para[0] = para[0].cut(m[1])
+			p.add(textBlock, withTags(tags, "div"), para)
+		} else if !p.comments(para) {
+			p.add(textBlock, withTags(tags, "p"), para)
+		}
+	}
+
+	return end
+}
+
+// adocParagraph returns the lines of the paragraph starting at lines[i],
+// along with the index of the line after it.
+//
+// A paragraph in a list item also ends at a list continuation or the start
+// of another item.
+func adocParagraph(lines []srcLine, i int, inList bool) (int, []srcLine) {
+	para := []srcLine{lines[i]}
+
+	end := i + 1
+	for ; end < len(lines); end++ {
+		text := lines[end].text
+		if lines[end].isBlank() || isADocDelimiter(text) || reADocBlockAttrs.MatchString(text) {
+			break
+		} else if inList && (text == "+" || isADocListItem(text)) {
+			break
+		} else if isADocComment(text) {
+			continue
+		}
+		para = append(para, lines[end])
+	}
+
+	return end, para
+}
+
+// comments adds the comments in a paragraph of passthroughs -- e.g.,
+// `pass:[<!-- vale off -->]` -- returning false if it has any other content.
+func (p *adocParser) comments(para []srcLine) bool {
+	comments := []srcBlock{}
+	for _, ln := range para {
+		m := reADocPassComment.FindStringSubmatchIndex(strings.TrimSpace(ln.text))
+		if m == nil {
+			return false
+		}
+		ln = ln.trim()
+		comment := srcLine{num: ln.num, off: ln.off + m[2], text: ln.text[m[2]:m[3]]}
+		comments = append(comments, srcBlock{kind: commentBlock, lines: []srcLine{comment}})
+	}
+	p.blocks = append(p.blocks, comments...)
+	return true
+}
+
+// item parses a list item whose marker ends at byte `start` of lines[i].
+func (p *adocParser) item(lines []srcLine, i, start int, tags []string) int {
+	end, text := adocParagraph(lines, i, true)
+	text[0] = text[0].cut(start)
+	p.add(textBlock, tags, text)
+	return p.attached(lines, end, tags)
+}
+
+// term parses a description list's term, given by the submatches m of
+// lines[i], and its description.
+func (p *adocParser) term(lines []srcLine, i int, m []int, tags []string) int {
+	ln := lines[i]
+	term := srcLine{num: ln.num, off: ln.off + m[2], text: ln.text[m[2]:m[3]]}
+	p.add(textBlock, withTags(tags, "dl", "dt"), []srcLine{term})
+
+	dd := withTags(tags, "dl", "dd")
+	if m[1] < len(ln.text) {
+		return p.item(lines, i, m[1], dd)
+	} else if i+1 < len(lines) && !lines[i+1].isBlank() && !isADocListItem(lines[i+1].text) {
+		// The description starts on the next line.
+		next := lines[i+1]
+		return p.item(lines, i+1, len(next.text)-len(strings.TrimLeft(next.text, " \t")), dd)
+	}
+
+	return p.attached(lines, i+1, dd)
+}
+
+// attached parses the blocks attached to a list item by list continuations
+// (i.e., a "+" on its own line).
+func (p *adocParser) attached(lines []srcLine, i int, tags []string) int {
+	for i < len(lines) && lines[i].text == "+" {
+		i++
+		meta := adocMeta{}
+		for i < len(lines) && isADocMeta(lines[i].text) {
+			i = p.element(lines, i, tags, &meta)
+		}
+		if i < len(lines) {
+			i = p.element(lines, i, tags, &meta)
+		}
+	}
+	return i
+}
+
+// delimited parses the delimited block starting at lines[i].
+func (p *adocParser) delimited(lines []srcLine, i int, tags []string, meta adocMeta) int {
+	open := lines[i].text
+	closing := open
+	if strings.HasPrefix(open, "```") {
+		closing = "```"
+	}
+
+	end := i + 1
+	for end < len(lines) && lines[end].text != closing {
+		end++
+	}
+	content := lines[i+1 : end]
+	if end < len(lines) {
+		end++
+	}
+
+	style := meta.style
+	switch c := open[0]; {
+	case reADocTable.MatchString(open):
+		p.table(content, c, tags, meta)
+	case c == '/':
+		// A comment block.
+	case c == '+':
+		// A passthrough block, whose only content we care about are comments.
+		for _, ln := range content {
+			for _, m := range reADocComment.FindAllStringSubmatchIndex(ln.text, -1) {
+				comment := srcLine{num: ln.num, off: ln.off + m[2], text: ln.text[m[2]:m[3]]}
+				p.blocks = append(p.blocks, srcBlock{kind: commentBlock, lines: []srcLine{comment}})
+			}
+		}
+	case c == '-' && open != "--", c == '.', c == '`':
+		p.add(textBlock, withTags(tags, "pre"), content)
+	case c == '_' && style == "verse":
+		p.paragraphs(content, withTags(tags, "blockquote"))
+	case c == '_':
+		p.body(content, withTags(tags, "blockquote"))
+	case isADocVerbatim(style):
+		p.add(textBlock, withTags(tags, "pre"), content)
+	case isADocRaw(style):
+	case style == "quote" || style == "verse":
+		p.body(content, withTags(tags, "blockquote"))
+	default:
+		// An example, sidebar, admonition, or open block.
+		p.body(content, withTags(tags, "div"))
+	}
+
+	return end
+}
+
+// An adocCell is a cell of a table.
+type adocCell struct {
+	spec  string    // the cell's specifier -- e.g., "2+" or "a"
+	row   int       // the line (of the table's content) that the cell starts on
+	lines []srcLine // the cell's content
+}
+
+// table parses a table's content, whose cells are separated by sep.
+func (p *adocParser) table(content []srcLine, sep byte, tags []string, meta adocMeta) {
+	if sep == ',' || sep == ':' || (meta.named["format"] != "" && meta.named["format"] != "psv") {
+		// We don't lint CSV or DSV data.
+		return
+	}
+
+	cells := adocCells(content, sep)
+	if len(cells) == 0 {
+		return
+	}
+
+	styles := adocColumns(meta.named["cols"])
+	cols := len(styles)
+	if cols == 0 {
+		for _, c := range cells {
+			if c.row == cells[0].row {
+				cols += adocSpan(c.spec)
+			}
+		}
+	}
+
+	// The header is either given explicitly or implied by a first line of
+	// cells that's followed by a blank line.
+	header := core.StringInSlice("header", meta.options)
+	implicit := !header && !core.StringInSlice("noheader", meta.options) &&
+		cells[0].row == 0 && len(content) > 1 && content[1].isBlank()
+
+	col := 0
+	for _, c := range cells {
+		style := ""
+		if m := reADocCellSpec.FindStringSubmatch(c.spec); m != nil && m[3] != "" {
+			style = m[3]
+		} else if cols > 0 && col%cols < len(styles) {
+			style = styles[col%cols]
+		}
+
+		tag := "td"
+		if (header && col < cols) || (implicit && c.row == 0) || style == "h" {
+			tag = "th"
+		}
+		col += adocSpan(c.spec)
+
+		cell := append([]srcLine{}, c.lines...)
+		cell[0] = cell[0].trim()
+
+		switch ctags := withTags(tags, "table", tag); style {
+		case "a":
+			p.body(dedentAll(cell), ctags)
+		case "l", "m":
+			p.add(textBlock, withTags(ctags, "pre"), cell)
+		default:
+			p.paragraphs(cell, ctags)
+		}
+	}
+}
+
+// adocCells splits a table's content into cells.
+func adocCells(content []srcLine, sep byte) []adocCell {
+	cells := []adocCell{}
+	for row, ln := range content {
+		text, pos := ln.text, 0
+		for k := 0; k < len(text); k++ {
+			if text[k] != sep || (k > 0 && text[k-1] == '\\') {
+				continue
+			}
+
+			// The cell's specifier, if any, immediately precedes the
+			// separator (and follows whitespace).
+			j := k
+			for j > pos && !unicode.IsSpace(rune(text[j-1])) {
+				j--
+			}
+			if (j > 0 && text[j-1] != ' ' && text[j-1] != '\t') || !reADocCellSpec.MatchString(text[j:k]) {
+				j = k
+			}
+
+			if len(cells) > 0 {
+				last := &cells[len(cells)-1]
+				last.lines = append(last.lines, srcLine{num: ln.num, off: ln.off + pos, text: text[pos:j]})
+			}
+			cells = append(cells, adocCell{spec: text[j:k], row: row})
+			pos = k + 1
+		}
+
+		if len(cells) > 0 {
+			last := &cells[len(cells)-1]
+			last.lines = append(last.lines, ln.cut(pos))
+		}
+	}
+	return cells
+}
+
+// adocSpan is the number of columns taken up by a cell with the given spec.
+func adocSpan(spec string) int {
+	m := reADocCellSpec.FindStringSubmatch(spec)
+	if m == nil {
+		return 1
+	}
+	for _, n := range m[1:3] {
+		if span, err := strconv.Atoi(n); err == nil && span > 0 {
+			return span
+		}
+	}
+	return 1
+}
+
+// adocColumns returns the style of each of the columns given by a table's
+// `cols` attribute -- e.g., "1,2a" or "3*".
+func adocColumns(cols string) []string {
+	styles := []string{}
+	if cols == "" {
+		return styles
+	}
+
+	for _, spec := range strings.FieldsFunc(cols, func(r rune) bool { return r == ',' || r == ';' }) {
+		spec = strings.TrimSpace(spec)
+
+		n := 1
+		if k := strings.Index(spec, "*"); k > 0 {
+			if count, err := strconv.Atoi(spec[:k]); err == nil {
+				n = count
+			}
+			spec = spec[k+1:]
+		}
+
+		style := ""
+		if spec != "" && strings.ContainsRune("aehlmdsv", rune(spec[len(spec)-1])) {
+			style = spec[len(spec)-1:]
+		}
+		for ; n > 0; n-- {
+			styles = append(styles, style)
+		}
+	}
+
+	return styles
+}
+
+// parse reads a block attribute list -- e.g., `[source,python]` or
+// `[quote, Author]`.
+func (m *adocMeta) parse(list string) {
+	if strings.HasPrefix(list, "[") {
+		// An anchor -- e.g., `[[id]]`.
+		return
+	} else if m.named == nil {
+		m.named = map[string]string{}
+	}
+
+	for idx, attr := range splitADocAttrs(list) {
+		attr = strings.TrimSpace(attr)
+		if k := strings.Index(attr, "="); k > 0 && !strings.ContainsAny(attr[:k], " \"'") {
+			key, value := strings.ToLower(attr[:k]), strings.Trim(attr[k+1:], "\"'")
+			if key == "options" || key == "opts" {
+				m.options = append(m.options, strings.Split(value, ",")...)
+			} else {
+				m.named[key] = value
+			}
+		} else if idx == 0 {
+			// The style, which may be followed by an ID, roles, and options
+			// -- e.g., `[source#id.role%linenums]`.
+			style := attr
+			if k := strings.IndexAny(attr, "#.%"); k >= 0 {
+				style = attr[:k]
+				for _, opt := range strings.Split(attr[k:], "%")[1:] {
+					if j := strings.IndexAny(opt, "#."); j >= 0 {
+						opt = opt[:j]
+					}
+					m.options = append(m.options, opt)
+				}
+			}
+			if style != "" {
+				m.style = strings.ToLower(style)
+			}
+		}
+	}
+}
+
+// splitADocAttrs splits an attribute list on the commas that aren't quoted.
+func splitADocAttrs(list string) []string {
+	attrs := []string{}
+
+	quote, start := rune(0), 0
+	for i, r := range list {
+		if quote != 0 {
+			if r == quote {
+				quote = 0
+			}
+		} else if r == '"' || r == '\'' {
+			quote = r
+		} else if r == ',' {
+			attrs = append(attrs, list[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(attrs, list[start:])
+}
+
+// adocAlt finds an image's alt text in its attribute list, s[open:end]: it's
+// either given by name or is the first positional attribute.
+func adocAlt(s string, open, end int) (int, int, bool) {
+	offset := open
+	for idx, attr := range splitADocAttrs(s[open:end]) {
+		start, stop := offset, offset+len(attr)
+		offset = stop + 1
+
+		trimmed := strings.TrimLeft(attr, " ")
+		if strings.HasPrefix(trimmed, "alt=") {
+			start += len(attr) - len(trimmed) + 4
+		} else if idx > 0 || strings.Contains(attr, "=") {
+			continue
+		}
+
+		for start < stop && strings.ContainsRune(` "'`, rune(s[start])) {
+			start++
+		}
+		for stop > start && strings.ContainsRune(` "'`, rune(s[stop-1])) {
+			stop--
+		}
+		if start < stop {
+			return start, stop, true
+		}
+	}
+	return 0, 0, false
+}
+
+func isADocDelimiter(text string) bool {
+	if text == "--" || strings.HasPrefix(text, "```") || reADocTable.MatchString(text) {
+		return true
+	} else if len(text) < 4 || !strings.ContainsRune("-.=*_+/", rune(text[0])) {
+		return false
+	}
+	return strings.Count(text, text[:1]) == len(text)
+}
+
+func isADocComment(text string) bool {
+	return strings.HasPrefix(text, "//") && !strings.HasPrefix(text, "///")
+}
+
+// isADocMeta determines if a line is block metadata (or a comment).
+func isADocMeta(text string) bool {
+	if isADocDelimiter(text) {
+		return false
+	}
+	return isADocComment(text) || reADocBlockAttrs.MatchString(text) || reADocBlockTitle.MatchString(text)
+}
+
+func isADocListItem(text string) bool {
+	for _, re := range []*regexp.Regexp{reADocUnordered, reADocOrdered, reADocCallout} {
+		if m := re.FindStringIndex(text); m != nil && m[1] < len(text) {
+			return true
+		}
+	}
+	return reADocTerm.MatchString(text)
+}
+
+// isADocVerbatim determines if a block's style means that its content is
+// code (or otherwise preformatted).
+func isADocVerbatim(style string) bool {
+	return core.StringInSlice(style, []string{"source", "listing", "literal"})
+}
+
+// isADocRaw determines if a block's style means that its content isn't
+// prose at all.
+func isADocRaw(style string) bool {
+	return core.StringInSlice(style, []string{"pass", "stem", "latexmath", "asciimath", "comment"})
+}
+
+func isADocAdmonition(style string) bool {
+	return core.StringInSlice(style, []string{"note", "tip", "important", "warning", "caution"})
+}
+
+// adocInline strips the inline markup from raw AsciiDoc.
+type adocInline struct {
+	raw     string
+	ignored []string
+	lookup  func(name string) (string, bool)
+
+	sb      strings.Builder
+	masked  []bool
+	spans   []inlineSpan
+	closers map[int]int // the length of each closing delimiter we expect
+}
+
+var reADocRef = regexp.MustCompile(`^\{(\w[\w-]*)(:[^}\n]*)?\}`)
+var reADocMacro = regexp.MustCompile(`^([a-z][a-z0-9]*):([^\s\[]*)\[`)
+var reADocURL = regexp.MustCompile(`^(?:https?|ftp|irc|file)://[^\s\[\]<>"]*[^\s\[\]<>".,;:!?)'"]`)
+var reADocURLText = regexp.MustCompile(`^((?:https?|ftp|irc|file)://[^\s\[\]]+)\[`)
+var reADocAngleURL = regexp.MustCompile(`^<((?:https?|ftp|irc|file)://[^\s>]+)>`)
+var reADocXref = regexp.MustCompile(`^<<([^<>,\n]+?)(?:,\s*([^>\n]+?))?>>`)
+var reADocAnchor = regexp.MustCompile(`^\[\[\[?[\w:][\w:.-]*(?:,[^\]\n]*)?\]\]\]?`)
+var reADocRole = regexp.MustCompile("^\\[[^\\[\\]\\n]*\\][*_#`]")
+var reADocNamedAttr = regexp.MustCompile(`,\s*[\w-]+=`)
+
+// adocEscapable are the characters that a backslash escapes.
+const adocEscapable = "*_`#^~+{[<(|!\\"
+
+func (p *adocInline) mask(start, end int) {
+	for i := start; i < end; i++ {
+		p.masked[i] = true
+	}
+}
+
+// emit writes the element raw[start:end] with the given tag.
+func (p *adocInline) emit(tag string, start, end int) {
+	p.spans = append(p.spans, inlineSpan{tag: tag, start: start, end: end})
+	if isIgnoredTag(tag, p.ignored) {
+		p.mask(start, end)
+		stars := strings.Repeat("*", utf8.RuneCountInString(p.raw[start:end]))
+		p.sb.WriteString(codify(".adoc", stars))
+	} else {
+		p.sb.WriteString(p.raw[start:end])
+	}
+}
+
+func (p *adocInline) parse() {
+	s := p.raw
+	for i := 0; i < len(s); {
+		if n, found := p.closers[i]; found {
+			delete(p.closers, i)
+			p.mask(i, i+n)
+			i += n
+			continue
+		} else if next := p.markup(i); next > i {
+			i = next
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(s[i:])
+		p.sb.WriteString(s[i : i+size])
+		i += size
+	}
+}
+
+// markup parses the inline markup (if any) starting at raw[i], returning
+// the index after it.
+func (p *adocInline) markup(i int) int {
+	s := p.raw
+
+	switch c := s[i]; c {
+	case '\\':
+		return p.escape(i)
+	case 0:
+		// A match of `TokenIgnores`.
+		end := i
+		for end < len(s) && s[end] == 0 {
+			end++
+		}
+		p.mask(i, end)
+		p.sb.WriteString(codify(".adoc", strings.Repeat("*", end-i)))
+		return end
+	case '+':
+		return p.plus(i)
+	case '`':
+		return p.backtick(i)
+	case '"', '\'':
+		// Curved quotes -- e.g., "`text`".
+		if strings.HasPrefix(s[i+1:], "`") {
+			if end := strings.Index(s[i+2:], "`"+string(c)); end > 0 {
+				p.sb.WriteByte(c)
+				p.mask(i+1, i+2)
+				p.closers[i+2+end] = 1
+				return i + 2
+			}
+		}
+	case '*':
+		return p.quote(i, "*", "strong")
+	case '_':
+		return p.quote(i, "_", "em")
+	case '#':
+		return p.quote(i, "#", "mark")
+	case '^', '~':
+		// Superscript and subscript text, which can't contain spaces.
+		end := strings.IndexByte(s[i+1:], c)
+		if end > 0 && !strings.ContainsAny(s[i+1:i+1+end], " \t\n") {
+			tag := "sup"
+			if c == '~' {
+				tag = "sub"
+			}
+			return p.format(i, i+1+end, 1, tag)
+		}
+	case '{':
+		return p.attribute(i)
+	case '[':
+		if m := reADocAnchor.FindStringIndex(s[i:]); m != nil {
+			p.mask(i, i+m[1])
+			return i + m[1]
+		} else if m := reADocRole.FindStringIndex(s[i:]); m != nil {
+			// The attributes (e.g., a role) of some formatted text.
+			p.mask(i, i+m[1]-1)
+			return i + m[1] - 1
+		}
+	case '<':
+		return p.xref(i)
+	case '(':
+		return p.index(i)
+	default:
+		if c >= 'a' && c <= 'z' && (i == 0 || !isADocWordRune(s, i-1)) {
+			return p.macro(i)
+		}
+	}
+
+	return i
+}
+
+// escape parses a backslash, which escapes the markup that follows it.
+func (p *adocInline) escape(i int) int {
+	s := p.raw
+	if i+1 == len(s) {
+		return i
+	}
+
+	r, size := utf8.DecodeRuneInString(s[i+1:])
+	if strings.ContainsRune(adocEscapable, r) {
+		p.mask(i, i+1)
+		p.sb.WriteString(s[i+1 : i+1+size])
+		return i + 1 + size
+	}
+
+	for _, re := range []*regexp.Regexp{reADocURL, reADocMacro} {
+		if m := re.FindStringIndex(s[i+1:]); m != nil {
+			p.mask(i, i+1)
+			p.sb.WriteString(s[i+1 : i+1+m[1]])
+			return i + 1 + m[1]
+		}
+	}
+
+	return i
+}
+
+// plus parses passthroughs -- e.g., +text+ -- and hard line breaks.
+func (p *adocInline) plus(i int) int {
+	s := p.raw
+
+	switch {
+	case i > 0 && s[i-1] == ' ' && (i+1 == len(s) || s[i+1] == '\n'):
+		p.mask(i, i+1)
+		return i + 1
+	case strings.HasPrefix(s[i:], "+++"):
+		// Raw content (e.g., HTML), which we skip.
+		if end := strings.Index(s[i+3:], "+++"); end >= 0 {
+			p.mask(i, i+6+end)
+			return i + 6 + end
+		}
+	case strings.HasPrefix(s[i:], "++"):
+		if end := adocClose(s, i+2, "++", false); end > 0 {
+			return p.passthrough(i, end, 2)
+		}
+	case adocOpens(s, i, 1):
+		if end := adocClose(s, i+1, "+", true); end > 0 {
+			return p.passthrough(i, end, 1)
+		}
+	}
+
+	return i
+}
+
+// passthrough writes the text raw[i+n:end], whose delimiters have length n,
+// as is.
+func (p *adocInline) passthrough(i, end, n int) int {
+	p.mask(i, i+n)
+	p.mask(end, end+n)
+	p.sb.WriteString(p.raw[i+n : end])
+	return end + n
+}
+
+func (p *adocInline) backtick(i int) int {
+	s := p.raw
+
+	if i > 0 && isADocWordRune(s, i-1) && strings.HasPrefix(s[i+1:], "'") {
+		// A curved apostrophe -- e.g., "Olaf`'s".
+		p.mask(i, i+1)
+		return i + 1
+	} else if strings.HasPrefix(s[i:], "``") {
+		if end := adocClose(s, i+2, "``", false); end > 0 {
+			return p.format(i, end, 2, "code")
+		}
+	}
+
+	if adocOpens(s, i, 1) {
+		if end := adocClose(s, i+1, "`", true); end > 0 {
+			return p.format(i, end, 1, "code")
+		}
+	}
+
+	return i
+}
+
+// quote parses the formatted text -- e.g., *strong* or __emphasis__ -- that
+// starts at raw[i].
+func (p *adocInline) quote(i int, mark, tag string) int {
+	s := p.raw
+
+	if strings.HasPrefix(s[i:], mark+mark) {
+		if end := adocClose(s, i+2, mark+mark, false); end > 0 {
+			return p.format(i, end, 2, tag)
+		}
+	}
+
+	if adocOpens(s, i, 1) {
+		if end := adocClose(s, i+1, mark, true); end > 0 {
+			return p.format(i, end, 1, tag)
+		}
+	}
+
+	return i
+}
+
+// format handles the formatted text raw[i+n:end], whose delimiters have
+// length n.
+func (p *adocInline) format(i, end, n int, tag string) int {
+	p.mask(i, i+n)
+	if tag == "code" || isIgnoredTag(tag, p.ignored) {
+		p.mask(end, end+n)
+		p.emit(tag, i+n, end)
+		return end + n
+	}
+
+	// The text may contain other markup, so we keep parsing it.
+	p.spans = append(p.spans, inlineSpan{tag: tag, start: i + n, end: end})
+	p.closers[end] = n
+
+	return i + n
+}
+
+// attribute parses an attribute reference, which we replace with the
+// attribute's value (or drop, if it isn't defined).
+func (p *adocInline) attribute(i int) int {
+	m := reADocRef.FindStringSubmatchIndex(p.raw[i:])
+	if m == nil {
+		return i
+	}
+
+	p.mask(i, i+m[1])
+	if m[4] < 0 {
+		if value, found := p.lookup(p.raw[i+m[2] : i+m[3]]); found {
+			p.sb.WriteString(value)
+		}
+	}
+
+	return i + m[1]
+}
+
+// xref parses a cross reference -- e.g., <<id,text>> -- or a URL in angle
+// brackets.
+func (p *adocInline) xref(i int) int {
+	s := p.raw
+
+	if m := reADocXref.FindStringSubmatchIndex(s[i:]); m != nil {
+		if m[4] < 0 {
+			// The text would be generated from the target.
+			p.mask(i, i+m[1])
+		} else {
+			p.mask(i, i+m[4])
+			p.emit("a", i+m[4], i+m[5])
+			p.mask(i+m[5], i+m[1])
+		}
+		return i + m[1]
+	} else if m := reADocAngleURL.FindStringSubmatchIndex(s[i:]); m != nil {
+		p.mask(i, i+1)
+		p.emit("a", i+m[2], i+m[3])
+		p.mask(i+m[3], i+m[1])
+		return i + m[1]
+	}
+
+	return i
+}
+
+// index parses an index term: ((term)) is visible, while (((term))) isn't.
+func (p *adocInline) index(i int) int {
+	s := p.raw
+
+	if strings.HasPrefix(s[i:], "(((") {
+		if end := strings.Index(s[i+3:], ")))"); end >= 0 {
+			p.mask(i, i+6+end)
+			return i + 6 + end
+		}
+	} else if strings.HasPrefix(s[i:], "((") {
+		if end := strings.Index(s[i+2:], "))"); end > 0 {
+			p.mask(i, i+2)
+			p.closers[i+2+end] = 2
+			return i + 2
+		}
+	}
+
+	return i
+}
+
+// macro parses an inline macro -- e.g., link:url[text] -- or a URL.
+func (p *adocInline) macro(i int) int {
+	s := p.raw
+
+	if m := reADocURLText.FindStringSubmatchIndex(s[i:]); m != nil {
+		if end := adocBracket(s, i+m[1]); end > 0 {
+			p.link(i, i, i+m[3], i+m[1], end)
+			return end + 1
+		}
+	}
+	if m := reADocURL.FindStringIndex(s[i:]); m != nil {
+		p.emit("a", i, i+m[1])
+		return i + m[1]
+	}
+
+	m := reADocMacro.FindStringSubmatchIndex(s[i:])
+	if m == nil {
+		return i
+	}
+	target, open := i+m[4], i+m[1]
+
+	end := adocBracket(s, open)
+	if end < 0 {
+		return i
+	}
+
+	switch s[i+m[2] : i+m[3]] {
+	case "link", "mailto":
+		p.link(i, target, i+m[5], open, end)
+	case "xref":
+		if start, stop := adocLinkText(s, open, end); start < stop {
+			p.link(i, target, i+m[5], open, end)
+		} else {
+			p.mask(i, end+1)
+		}
+	case "image":
+		if start, stop, found := adocAlt(s, open, end); found {
+			p.spans = append(p.spans, inlineSpan{attr: "alt", start: start, end: stop})
+		}
+		p.mask(i, end+1)
+	case "footnote", "footnoteref", "indexterm2":
+		// The text is visible, and may contain other markup.
+		p.mask(i, open)
+		p.closers[end] = 1
+		return open
+	case "kbd", "btn", "stem", "latexmath", "asciimath":
+		tag := map[string]string{"kbd": "kbd", "btn": "b"}[s[i+m[2]:i+m[3]]]
+		if tag == "" {
+			tag = "code"
+		}
+		p.mask(i, open)
+		p.emit(tag, open, end)
+		p.mask(end, end+1)
+	case "menu":
+		p.mask(i, target)
+		p.emit("span", target, i+m[5])
+		p.mask(i+m[5], open)
+		if open < end {
+			p.sb.WriteString(" ")
+			p.emit("span", open, end)
+		}
+		p.mask(end, end+1)
+	case "pass", "anchor", "indexterm", "icon":
+		p.mask(i, end+1)
+	default:
+		return i
+	}
+
+	return end + 1
+}
+
+// link handles a link to raw[ts:te] whose text (which may be followed by
+// other attributes) is in raw[open:end].
+func (p *adocInline) link(i, ts, te, open, end int) {
+	start, stop := adocLinkText(p.raw, open, end)
+	if start == stop {
+		// The target is shown instead.
+		start, stop = ts, te
+	}
+	p.mask(i, start)
+	p.emit("a", start, stop)
+	p.mask(stop, end+1)
+}
+
+// adocLinkText finds a link's text in its attribute list, s[open:end].
+func adocLinkText(s string, open, end int) (int, int) {
+	start, stop := open, end
+	if strings.HasPrefix(s[open:end], "\"") {
+		if k := strings.Index(s[open+1:end], "\""); k >= 0 {
+			return open + 1, open + 1 + k
+		}
+	} else if m := reADocNamedAttr.FindStringIndex(s[open:end]); m != nil {
+		stop = open + m[0]
+	}
+
+	for start < stop && s[start] == ' ' {
+		start++
+	}
+	for stop > start && (s[stop-1] == '^' || s[stop-1] == ' ') {
+		stop--
+	}
+	return start, stop
+}
+
+// adocBracket finds the "]" that closes the attribute list starting at
+// s[open], returning -1 if there isn't one.
+func adocBracket(s string, open int) int {
+	depth := 0
+	for k := open; k < len(s); k++ {
+		switch s[k] {
+		case '\\':
+			k++
+		case '[':
+			depth++
+		case ']':
+			if depth == 0 {
+				return k
+			}
+			depth--
+		}
+	}
+	return -1
+}
+
+// adocOpens determines if raw[i:i+n] can open constrained formatting: it
+// can't follow a word character and has to be followed by a non-space.
+func adocOpens(s string, i, n int) bool {
+	if i > 0 {
+		r, _ := utf8.DecodeLastRuneInString(s[:i])
+		if isADocWordRune(s, i-1) || r == ';' || r == ':' || r == '}' {
+			return false
+		}
+	}
+	if i+n >= len(s) {
+		return false
+	}
+	r, _ := utf8.DecodeRuneInString(s[i+n:])
+	return !unicode.IsSpace(r)
+}
+
+// adocClose finds the closing delimiter of the formatted text that starts
+// at raw[from], returning -1 if there isn't one.
+func adocClose(s string, from int, delim string, constrained bool) int {
+	for k := from + 1; k+len(delim) <= len(s); k++ {
+		if !strings.HasPrefix(s[k:], delim) || s[k-1] == '\\' {
+			continue
+		} else if constrained {
+			// Constrained formatting has to end a word.
+			r, _ := utf8.DecodeLastRuneInString(s[:k])
+			if unicode.IsSpace(r) || (k+len(delim) < len(s) && isADocWordRune(s, k+len(delim))) {
+				continue
+			}
+		}
+		return k
+	}
+	return -1
+}
+
+// isADocWordRune determines if the rune that includes raw[i] is a word
+// character.
+func isADocWordRune(s string, i int) bool {
+	for i > 0 && !utf8.RuneStart(s[i]) {
+		i--
+	}
+	r, _ := utf8.DecodeRuneInString(s[i:])
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// lintADocNative lints an AsciiDoc file with our own parser.
+func (l Linter) lintADocNative(f *core.File) error {
+	src, err := l.blankIgnored(f.Content, ".adoc")
+	if err != nil {
+		return core.NewE100(f.Path, err)
+	}
+	// Content that isn't in a file (e.g., from stdin or a language server
+	// client) can't include anything (see `parseADoc`).
+	path := l.abs(f.Path)
+	if !core.FileExists(path) {
+		path = ""
+	}
+
+	blocks, inline := parseADoc(src, path)
+	l.lintParsed(f, src, blocks, inline)
+	return nil
+}
@@ -0,0 +1,332 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseADoc(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		src    string
+		blocks []string
+	}{
+		{
+			name: "sections",
+			src:  "= Doc Title\nAuthor Name\nv1.0\n\nPreamble.\n\n== Section\n\n=== Sub\n\n## Markdown\n",
+			blocks: []string{
+				"h1 1:3 Doc Title",
+				"p 5:1 Preamble.",
+				"h2 7:4 Section",
+				"h3 9:5 Sub",
+				"h2 11:4 Markdown",
+			},
+		},
+		{
+			name: "paragraphs",
+			src:  "A paragraph\nthat wraps.\n\n  A literal paragraph.\n",
+			blocks: []string{
+				"p 1:1 A paragraph | 2:1 that wraps.",
+				"pre 4:1   A literal paragraph.",
+			},
+		},
+		{
+			name: "lists",
+			src:  "* One\n* Two\ncontinued\n** Nested\n\n. First\n. Second\n\n1. Numbered\n",
+			blocks: []string{
+				"ul/li 1:3 One",
+				"ul/li 2:3 Two | 3:1 continued",
+				"ul/li 4:4 Nested",
+				"ol/li 6:3 First",
+				"ol/li 7:3 Second",
+				"ol/li 9:4 Numbered",
+			},
+		},
+		{
+			name: "description lists",
+			src:  "Term:: Definition.\nOther::\n  Next line.\n",
+			blocks: []string{
+				"dl/dt 1:1 Term",
+				"dl/dd 1:8 Definition.",
+				"dl/dt 2:1 Other",
+				"dl/dd 3:3 Next line.",
+			},
+		},
+		{
+			name:   "list continuations",
+			src:    "* Item\n+\nAttached paragraph.\n",
+			blocks: []string{"ul/li 1:3 Item", "ul/li/p 3:1 Attached paragraph."},
+		},
+		{
+			name: "admonitions",
+			src:  "NOTE: Inline note.\n\n[WARNING]\nStyled warning.\n\n[TIP]\n====\nBlock tip.\n====\n",
+			blocks: []string{
+				"div 1:7 Inline note.",
+				"div 4:1 Styled warning.",
+				"div/p 8:1 Block tip.",
+			},
+		},
+		{
+			name: "listing and literal blocks",
+			src:  "[source,python]\n----\nvery = 1\n----\n\n....\nliteral\n....\n\n[source]\nvery = 2\n\n```\nfenced\n```\n",
+			blocks: []string{
+				"pre 3:1 very = 1",
+				"pre 7:1 literal",
+				"pre 11:1 very = 2",
+				"pre 14:1 fenced",
+			},
+		},
+		{
+			name: "quotes",
+			src:  "[quote, Author]\n____\nQuoted text.\n____\n\n[verse]\n____\nLine one\n\nLine two\n____\n\n> Markdown quote\n",
+			blocks: []string{
+				"blockquote/p 3:1 Quoted text.",
+				"blockquote 8:1 Line one",
+				"blockquote 10:1 Line two",
+				"blockquote/p 13:3 Markdown quote",
+			},
+		},
+		{
+			name: "titles, sidebars, and open blocks",
+			src:  ".A block title\n****\nSidebar text.\n****\n\n--\nOpen block.\n--\n",
+			blocks: []string{
+				"div 1:2 A block title",
+				"div/p 3:1 Sidebar text.",
+				"div/p 7:1 Open block.",
+			},
+		},
+		{
+			name: "comments and passthroughs",
+			src:  "////\nA comment block.\n////\n\n// A line comment\n\n++++\n<!-- vale off -->\n++++\n\npass:[<!-- vale on -->]\n",
+			blocks: []string{
+				"comment 5:3  A line comment",
+				"comment 8:6 vale off",
+				"comment 11:12 vale on",
+			},
+		},
+		{
+			name: "tables",
+			src:  "[cols=\"1,1a\"]\n|===\n|H1 |H2\n\n|c1 |* item\n|===\n",
+			blocks: []string{
+				"table/th 3:2 H1",
+				"table/th/p 3:6 H2",
+				"table/td 5:2 c1",
+				"table/td/ul/li 5:8 item",
+			},
+		},
+		{
+			name: "explicit headers and CSV tables",
+			src:  "[%header]\n|===\n|A |B\n|c |d\n|===\n\n[format=csv]\n|===\na,b\n|===\n",
+			blocks: []string{
+				"table/th 3:2 A",
+				"table/th 3:5 B",
+				"table/td 4:2 c",
+				"table/td 4:5 d",
+			},
+		},
+		{
+			name:   "images and breaks",
+			src:    "image::a.png[Alt text]\n\nimage::b.png[alt=\"Named alt\",width=10]\n\n'''\n\n<<<\n",
+			blocks: []string{"attr:alt 1:14 Alt text", "attr:alt 3:19 Named alt"},
+		},
+		{
+			// Attributes are substituted by the inline parser.
+			name:   "attributes",
+			src:    ":name: World\n\nHello {name} and {undefined}.\n",
+			blocks: []string{"p 3:1 Hello {name} and {undefined}."},
+		},
+		{
+			name:   "unterminated blocks",
+			src:    "Unterminated\n----\ncode\n",
+			blocks: []string{"p 1:1 Unterminated", "pre 3:1 code | 4:1 "},
+		},
+		{
+			name:   "empty tables",
+			src:    "|===\n",
+			blocks: []string{},
+		},
+		{
+			name:   "empty documents",
+			src:    "",
+			blocks: []string{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			blocks, _ := parseADoc(tc.src, "")
+			if got := describeBlocks(blocks); !reflect.DeepEqual(got, tc.blocks) {
+				t.Errorf("expected:\n%s\ngot:\n%s",
+					strings.Join(tc.blocks, "\n"), strings.Join(got, "\n"))
+			}
+		})
+	}
+}
+
+func TestParseADocInline(t *testing.T) {
+	src := ":product: Vale\n:!unset:\n\nUse *{product}* with `code`, {unset}, and link:https://example.com[a link].\n"
+
+	blocks, inline := parseADoc(src, "")
+	if len(blocks) != 1 {
+		t.Fatalf("expected 1 block, got %v", describeBlocks(blocks))
+	}
+
+	in := inline(blocks[0].lines[0].text, []string{"tt", "code"})
+	// Like Asciidoctor (see `adocArgs`), we drop undefined attributes.
+	if expected := "Use Vale with `****`, , and a link."; in.text != expected {
+		t.Errorf("expected = %q, got = %q", expected, in.text)
+	}
+}
+
+func TestParseADocConditionals(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		src  string
+		text []string
+	}{
+		{
+			name: "ifdef",
+			src:  ":a:\n\nifdef::a[]\nKept.\nendif::[]\nifdef::b[]\nDropped.\nendif::[]\n",
+			text: []string{"Kept."},
+		},
+		{
+			name: "ifndef",
+			src:  ":a:\n\nifndef::a[]\nDropped.\nendif::[]\nifndef::b[]\nKept.\nendif::[]\n",
+			text: []string{"Kept."},
+		},
+		{
+			name: "any and all",
+			src:  ":a:\n\nifdef::a,b[]\nAny.\nendif::[]\nifdef::a+b[]\nAll.\nendif::[]\n",
+			text: []string{"Any."},
+		},
+		{
+			name: "single-line",
+			src:  ":a:\n\nifdef::a[Kept.]\nifdef::b[Dropped.]\n",
+			text: []string{"Kept."},
+		},
+		{
+			name: "nested",
+			src:  ":a:\n\nifdef::b[]\nifdef::a[]\nDropped.\nendif::[]\nendif::[]\nAfter.\n",
+			text: []string{"After."},
+		},
+		{
+			// We can't evaluate expressions, so we lint their content.
+			name: "ifeval",
+			src:  "ifeval::[{x} > 1]\nKept.\nendif::[]\n",
+			text: []string{"Kept."},
+		},
+		{
+			name: "unset attributes",
+			src:  ":a:\n:a!:\n\nifdef::a[]\nDropped.\nendif::[]\n",
+			text: []string{},
+		},
+		{
+			name: "unbalanced endif",
+			src:  "endif::[]\nKept.\n",
+			text: []string{"Kept."},
+		},
+		{
+			name: "unterminated ifdef",
+			src:  "ifdef::a[]\nDropped.\n",
+			text: []string{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			blocks, _ := parseADoc(tc.src, "")
+
+			text := []string{}
+			for _, b := range blocks {
+				text = append(text, rawText(b.lines))
+			}
+			if !reflect.DeepEqual(text, tc.text) {
+				t.Errorf("expected = %v, got = %v", tc.text, text)
+			}
+		})
+	}
+}
+
+func TestParseADocIncludes(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "docs")
+
+	writeFiles(t, root, map[string]string{
+		"outside.adoc":          ":outside:\n",
+		"docs/attrs.adoc":       ":included:\ninclude::nested/more.adoc[]\n",
+		"docs/nested/more.adoc": ":nested:\ninclude::../attrs.adoc[]\n",
+		"docs/self.adoc":        ":self:\ninclude::self.adoc[]\n",
+		"docs/large.adoc":       ":large:\n" + strings.Repeat("x", adocMaxInclude),
+	})
+
+	for _, tc := range []struct {
+		name    string
+		include string
+		defined string
+		want    bool
+	}{
+		{"relative", "attrs.adoc", "included", true},
+		{"nested", "attrs.adoc", "nested", true},
+		{"attribute in target", "{base}.adoc", "included", true},
+		{"cycle", "self.adoc", "self", true},
+		{"missing", "missing.adoc", "missing", false},
+		{"outside of the document's directory", "../outside.adoc", "outside", false},
+		{"absolute path outside of the document's directory", filepath.Join(root, "outside.adoc"), "outside", false},
+		{"too large", "large.adoc", "large", false},
+		{"URL", "https://example.com/attrs.adoc", "remote", false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			src := ":base: attrs\n\ninclude::" + tc.include + "[]\n\nifdef::" + tc.defined + "[]\nDefined.\nendif::[]\n"
+
+			blocks, _ := parseADoc(src, filepath.Join(dir, "a.adoc"))
+			if found := len(blocks) == 1; found != tc.want {
+				t.Errorf("expected '%s' to be defined = %v, got %v", tc.defined, tc.want, describeBlocks(blocks))
+			}
+		})
+	}
+}
+
+func TestParseADocIncludeSymlink(t *testing.T) {
+	root := t.TempDir()
+
+	writeFiles(t, root, map[string]string{"outside.adoc": ":outside:\n"})
+	if err := os.MkdirAll(filepath.Join(root, "docs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(root, "docs", "link.adoc")
+	if err := os.Symlink(filepath.Join(root, "outside.adoc"), link); err != nil {
+		t.Skip("symlinks aren't supported")
+	}
+
+	src := "include::link.adoc[]\n\nifdef::outside[]\nDefined.\nendif::[]\n"
+	if blocks, _ := parseADoc(src, filepath.Join(root, "docs", "a.adoc")); len(blocks) != 0 {
+		t.Errorf("expected a link out of the document's directory to be skipped, got %v",
+			describeBlocks(blocks))
+	}
+}
+
+func TestADocIncludeFromStdin(t *testing.T) {
+	dir := t.TempDir()
+
+	linter := testLinter(t, dir, map[string]string{
+		"attrs.adoc": ":internal:\n",
+	})
+	linter.Manager.Config.InExt = ".adoc"
+
+	// Content that isn't in a file (e.g., from a language server client)
+	// can't include files, even from the current directory.
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	if err = os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	linted, err := linter.LintString("include::attrs.adoc[]\n\nifdef::internal[]\nThis is very internal.\nendif::[]\n")
+	if err != nil {
+		t.Fatal(err)
+	} else if len(linted[0].Alerts) != 0 {
+		t.Errorf("expected no alerts, got %v", linted[0].Alerts)
+	}
+}
@@ -46,8 +46,14 @@ func (l Linter) lintHTMLTokens(f *core.File, raw []byte, offset int) {
 	if len(l.Manager.Config.SkippedScopes) > 0 {
 		skipTags = l.Manager.Config.SkippedScopes
 	}
+
+	// NOTE: We don't update `skipClasses` in place since the same Linter may
+	// be used to lint many documents (e.g., when running as a server).
+	classes := skipClasses
 	if len(l.Manager.Config.IgnoredClasses) > 0 {
-		skipClasses = append(skipClasses, l.Manager.Config.IgnoredClasses...)
+		classes = append(
+			append([]string{}, skipClasses...),
+			l.Manager.Config.IgnoredClasses...)
 	}
 
 	skipped := []string{"tt", "code"}
@@ -58,7 +64,7 @@ func (l Linter) lintHTMLTokens(f *core.File, raw []byte, offset int) {
 	walker := newWalker(f, raw, offset)
 	for {
 		tokt, tok, txt := walker.walk()
-		skipClass = checkClasses(attr, skipClasses)
+		skipClass = checkClasses(attr, classes)
 		if tokt == html.ErrorToken {
 			break
 		} else if tokt == html.StartTagToken && core.StringInSlice(txt, skipTags) {
@@ -116,19 +122,11 @@ func (l Linter) lintHTMLTokens(f *core.File, raw []byte, offset int) {
 }
 
 func (l Linter) lintScope(f *core.File, state walker, txt string) {
-	for _, tag := range state.tagHistory {
-		scope, match := tagToScope[tag]
-		if (match && !core.StringInSlice(tag, inlineTags)) || heading.MatchString(tag) {
-			if match {
-				scope = scope + f.RealExt
-			} else {
-				scope = "text.heading." + tag + f.RealExt
-			}
-			txt = strings.TrimLeft(txt, " ")
-			b := state.block(txt, scope)
-			l.lintBlock(f, b, state.lines, 0, false)
-			return
-		}
+	if scope, found := scopeOf(state.tagHistory, f.RealExt); found {
+		txt = strings.TrimLeft(txt, " ")
+		b := state.block(txt, scope)
+		l.lintBlock(f, b, state.lines, 0, false)
+		return
 	}
 
 	// NOTE: We don't include headings, list items, or table cells (which are
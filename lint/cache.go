@@ -0,0 +1,232 @@
+package lint
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/errata-ai/vale/v2/config"
+	"github.com/errata-ai/vale/v2/core"
+)
+
+// userCacheDir returns the directory that holds the caches for every project
+// (see `os.UserCacheDir`).
+//
+// NOTE: This is a variable so that our tests don't touch the real one.
+var userCacheDir = func() (string, error) {
+	dir, err := os.UserCacheDir()
+	return filepath.Join(dir, "vale"), err
+}
+
+// cacheVersion should be bumped whenever the format of a cache entry changes.
+const cacheVersion = 2
+
+// A cache stores the alerts for previously-linted files.
+//
+// Each entry is keyed by a hash of the file's path, content, and settings
+// (e.g., its BasedOnStyles and any nested configurations' rule options), and
+// entries are stored in a directory named after a hash of everything else
+// that can affect the results: the resolved configuration, the .vale.ini
+// file, every file on the StylesPath, and the Vale executable itself.
+// Changing any of these means that the entire cache is discarded.
+type cache struct {
+	dir string
+}
+
+// newCache prepares the cache for the given configuration.
+//
+// Each project -- i.e., each .vale.ini file -- has its own directory in the
+// user's cache directory, so we never write to the project itself.
+//
+// The cache is best-effort: if it can't be created (e.g., on a read-only
+// file system), we return nil and lint everything as usual.
+func newCache(cfg *config.Config) *cache {
+	base, err := userCacheDir()
+	if err != nil {
+		return nil
+	}
+
+	project := cfg.Path
+	if project == "" {
+		project = "."
+	}
+	project = absPath(project)
+
+	key, err := configKey(cfg)
+	if err != nil {
+		return nil
+	}
+
+	h := sha256.New()
+	writeFields(h, project)
+	root := filepath.Join(base, hex.EncodeToString(h.Sum(nil)))
+
+	// Remove the entries from any previous configurations of this project.
+	entries, _ := ioutil.ReadDir(root)
+	for _, entry := range entries {
+		if entry.Name() != key {
+			os.RemoveAll(filepath.Join(root, entry.Name()))
+		}
+	}
+
+	dir := filepath.Join(root, key)
+	if err = os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil
+	}
+
+	return &cache{dir: dir}
+}
+
+// get loads the cached alerts for f, which is stored at the absolute path
+// and configured by the given nested configurations, if there are any.
+func (c *cache) get(f *core.File, path string, layers []nestedConfig) ([]core.Alert, bool) {
+	var alerts []core.Alert
+
+	data, err := ioutil.ReadFile(c.entry(f, path, layers))
+	if err != nil {
+		return alerts, false
+	} else if err = json.Unmarshal(data, &alerts); err != nil {
+		return alerts, false
+	}
+
+	return alerts, true
+}
+
+// put stores the alerts of f, which is stored at the absolute path and
+// configured by the given nested configurations.
+func (c *cache) put(f *core.File, path string, layers []nestedConfig) {
+	alerts := f.Alerts
+	if alerts == nil {
+		alerts = []core.Alert{}
+	}
+
+	data, err := json.Marshal(alerts)
+	if err != nil {
+		return
+	}
+
+	// Write to a temporary file first, so that a concurrent (or interrupted)
+	// run never sees a partial entry.
+	tmp, err := ioutil.TempFile(c.dir, "tmp-")
+	if err != nil {
+		return
+	}
+
+	_, err = tmp.Write(data)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+
+	if err != nil || os.Rename(tmp.Name(), c.entry(f, path, layers)) != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+func (c *cache) entry(f *core.File, path string, layers []nestedConfig) string {
+	// A file's settings can differ from the root configuration's (see
+	// nested.go), so they're part of its key -- along with the rule options
+	// of its nested configurations, since `f.Options` only names the
+	// sections that they come from.
+	options := []interface{}{}
+	for _, layer := range layers {
+		options = append(options, layer.cfg.Path, layer.cfg.RuleOpts)
+	}
+	settings, _ := json.Marshal([]interface{}{
+		f.BaseStyles, f.Checks, f.Levels, f.Options, options})
+
+	h := sha256.New()
+	writeFields(h, path, f.NormedExt, f.Format, f.Content, string(settings))
+
+	return filepath.Join(c.dir, hex.EncodeToString(h.Sum(nil))+".json")
+}
+
+// configKey hashes everything, other than a file itself, that can affect the
+// alerts reported for it.
+func configKey(cfg *config.Config) (string, error) {
+	h := sha256.New()
+
+	// Settings that don't affect the results shouldn't invalidate the cache.
+	resolved := *cfg
+	resolved.Concurrency = 0
+
+	data, err := json.Marshal(resolved)
+	if err != nil {
+		return "", err
+	}
+
+	parsers, err := json.Marshal([]interface{}{cfg.Parsers, cfg.Commands})
+	if err != nil {
+		return "", err
+	}
+
+	writeFields(h,
+		strconv.Itoa(cacheVersion),
+		string(data),
+		string(parsers),
+		strconv.FormatBool(cfg.Simple),
+		cfg.InExt)
+
+	if exe, err := os.Executable(); err == nil {
+		if fi, err := os.Stat(exe); err == nil {
+			writeFields(h, exe, fi.ModTime().String(), strconv.FormatInt(fi.Size(), 10))
+		}
+	}
+
+	if core.FileExists(cfg.Path) {
+		if err = hashFile(h, cfg.Path); err != nil {
+			return "", err
+		}
+	}
+
+	if cfg.StylesPath != "" && core.IsDir(cfg.StylesPath) {
+		files := []string{}
+		err = filepath.Walk(cfg.StylesPath, func(fp string, fi os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			} else if !fi.IsDir() {
+				files = append(files, fp)
+			}
+			return nil
+		})
+		if err != nil {
+			return "", err
+		}
+
+		sort.Strings(files)
+		for _, fp := range files {
+			if err = hashFile(h, fp); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func hashFile(h hash.Hash, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	writeFields(h, filepath.ToSlash(path))
+	_, err = io.Copy(h, f)
+
+	return err
+}
+
+// writeFields adds each of the given fields to h, separated by a null byte.
+func writeFields(h hash.Hash, fields ...string) {
+	for _, field := range fields {
+		h.Write([]byte(field))
+		h.Write([]byte{0})
+	}
+}
@@ -0,0 +1,109 @@
+package lint
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/errata-ai/vale/v2/config"
+	"github.com/errata-ai/vale/v2/core"
+)
+
+func TestCache(t *testing.T) {
+	dir := t.TempDir()
+
+	styles := filepath.Join(dir, "styles")
+	if err := os.Mkdir(styles, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	rule := filepath.Join(styles, "Rule.yml")
+	if err := ioutil.WriteFile(rule, []byte("extends: existence\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, _ := config.New()
+	cfg.Path = filepath.Join(dir, ".vale.ini")
+	cfg.StylesPath = styles
+
+	base := filepath.Join(dir, "cache")
+	defer func(f func() (string, error)) { userCacheDir = f }(userCacheDir)
+	userCacheDir = func() (string, error) { return base, nil }
+
+	path := filepath.Join(dir, "test.md")
+	f := &core.File{Path: path, Content: "Some text."}
+	f.Alerts = []core.Alert{{Check: "Test.Rule", Line: 1, Span: []int{1, 4}}}
+
+	c := newCache(cfg)
+	if c == nil {
+		t.Fatal("expected a cache")
+	}
+	c.put(f, path, nil)
+
+	if alerts, found := c.get(f, path, nil); !found || len(alerts) != 1 {
+		t.Fatalf("expected 1 cached alert, got %v (found = %v)", alerts, found)
+	}
+
+	f.Content = "Some other text."
+	if _, found := c.get(f, path, nil); found {
+		t.Error("expected a miss after the file changed")
+	}
+	f.Content = "Some text."
+
+	// Changing a style invalidates the entire cache.
+	if err := ioutil.WriteFile(rule, []byte("extends: substitution\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, found := newCache(cfg).get(f, path, nil); found {
+		t.Error("expected a miss after the StylesPath changed")
+	}
+
+	// Nothing is written next to the configuration, and the old entries
+	// (only) are gone.
+	if core.FileExists(filepath.Join(dir, ".vale-cache")) {
+		t.Error("expected no cache in the project")
+	}
+	projects, err := ioutil.ReadDir(base)
+	if err != nil || len(projects) != 1 {
+		t.Fatalf("expected 1 project, got %v (%v)", projects, err)
+	}
+	entries, err := ioutil.ReadDir(filepath.Join(base, projects[0].Name()))
+	if err != nil || len(entries) != 1 {
+		t.Errorf("expected 1 configuration, got %v (%v)", entries, err)
+	}
+}
+
+func TestCacheNestedOptions(t *testing.T) {
+	dir := t.TempDir()
+
+	base := filepath.Join(dir, "cache")
+	defer func(f func() (string, error)) { userCacheDir = f }(userCacheDir)
+	userCacheDir = func() (string, error) { return base, nil }
+
+	lint := func(nested string) []core.Alert {
+		linter := testLinter(t, dir, map[string]string{
+			"sub/.vale.ini": nested,
+			"sub/a.md":      "This is very nice.\n",
+		})
+		linter.Manager.Config.NoCache = false
+
+		linted, err := linter.Lint([]string{filepath.Join(dir, "sub", "a.md")}, "*")
+		if err != nil {
+			t.Fatal(err)
+		} else if len(linted) != 1 {
+			t.Fatalf("expected 1 file, got %d", len(linted))
+		}
+		return linted[0].Alerts
+	}
+
+	if alerts := lint("[*.md]\nTest.Very.tokens = [very]\n"); len(alerts) != 1 || alerts[0].Match != "very" {
+		t.Fatalf("expected an alert for 'very', got %v", alerts)
+	}
+
+	// The nested configuration isn't part of the cache's directory, but its
+	// options are part of the file's entry.
+	if alerts := lint("[*.md]\nTest.Very.tokens = [nice]\n"); len(alerts) != 1 || alerts[0].Match != "nice" {
+		t.Errorf("expected an alert for 'nice', got %v", alerts)
+	}
+}
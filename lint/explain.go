@@ -0,0 +1,183 @@
+package lint
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/errata-ai/vale/v2/core"
+	"github.com/gobwas/glob"
+)
+
+// An Explanation describes the effective configuration for a single file.
+type Explanation struct {
+	Path       string       // the file's path
+	Sections   []string     // the sections that match the file
+	BaseStyles []string     // the file's effective BasedOnStyles
+	Rules      []RuleStatus // every loaded rule
+}
+
+// A RuleStatus describes whether (and why) a rule applies to a file.
+type RuleStatus struct {
+	Name   string // the rule's name (e.g., "Vale.Spelling")
+	Level  string // the rule's effective level
+	Scope  string // the scope that the rule runs on (e.g., "sentence")
+	Runs   bool   // does the rule apply to the file?
+	Reason string // why the rule does (or doesn't) apply to the file
+}
+
+// Explain reports which rules would run for the file at path, and why.
+//
+// The explanation mirrors the logic of `shouldRun`: it accounts for the file's
+// sections (including those from nested configuration files), rule levels,
+// and MinAlertLevel. In-text comments and scopes are evaluated while linting,
+// so they aren't considered here.
+func (l *Linter) Explain(path string) (*Explanation, error) {
+	if err := l.loadNested([]string{path}); err != nil {
+		return nil, err
+	}
+
+	f, err := core.NewFile(path, l.Manager.Config)
+	if err != nil {
+		return nil, err
+	}
+	l.applyNested(f)
+
+	exp := Explanation{
+		Path:       path,
+		Sections:   l.sections(path),
+		BaseStyles: f.BaseStyles,
+		Rules:      []RuleStatus{}}
+
+	names := []string{}
+	for name := range l.Manager.Rules() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		details := l.rule(name, f).Fields()
+
+		// NOTE: Consistency checks are configured by their "Style.Rule"
+		// prefix (see `shouldRun`).
+		key := name
+		if strings.Count(name, ".") > 1 {
+			list := strings.Split(name, ".")
+			key = strings.Join([]string{list[0], list[1]}, ".")
+		}
+
+		level := f.Level(key, details.Level)
+		status := l.status(key, f, details)
+
+		exp.Rules = append(exp.Rules, RuleStatus{
+			Name:   name,
+			Level:  level,
+			Scope:  details.Scope,
+			Runs:   status.runs(),
+			Reason: l.reason(status, key, level, path)})
+	}
+
+	return &exp, nil
+}
+
+func (l *Linter) reason(status ruleStatus, name, level, path string) string {
+	style := strings.Split(name, ".")[0]
+
+	switch status {
+	case belowMinLevel:
+		min := core.AlertLevels[l.Manager.Config.MinAlertLevel]
+		return fmt.Sprintf("'%s' is below MinAlertLevel ('%s')", level, min)
+	case sectionEnabled:
+		return "enabled in " + l.origin(name, path)
+	case sectionDisabled:
+		return "disabled in " + l.origin(name, path)
+	case globalEnabled:
+		return "enabled in [*]"
+	case globalDisabled:
+		return "disabled in [*]"
+	case inBaseStyle:
+		return fmt.Sprintf("'%s' is in BasedOnStyles", style)
+	default:
+		return fmt.Sprintf("'%s' isn't in BasedOnStyles", style)
+	}
+}
+
+// sections returns every section, from both the root and any nested
+// configuration files, that matches path.
+func (l *Linter) sections(path string) []string {
+	found := []string{}
+
+	fp := l.normalize(path)
+	for _, sec := range sortedSections(l.Manager.Config.SecToPat) {
+		if l.Manager.Config.SecToPat[sec].Match(fp) {
+			found = append(found, "["+sec+"]")
+		}
+	}
+
+	abs := absPath(path)
+	for _, layer := range l.layers(abs) {
+		label := l.nestedLabel(layer)
+		if len(layer.cfg.GBaseStyles)+len(layer.cfg.GChecks) > 0 {
+			found = append(found, fmt.Sprintf("[*] (%s)", label))
+		}
+
+		rel := layer.relative(l.normalize(abs))
+		for _, sec := range sortedSections(layer.cfg.SecToPat) {
+			if layer.cfg.SecToPat[sec].Match(rel) {
+				found = append(found, fmt.Sprintf("[%s] (%s)", sec, label))
+			}
+		}
+	}
+
+	return found
+}
+
+// origin finds the section that sets the rule name for path, starting with
+// the innermost nested configuration.
+func (l *Linter) origin(name, path string) string {
+	abs := absPath(path)
+
+	layers := l.layers(abs)
+	for i := len(layers) - 1; i >= 0; i-- {
+		layer := layers[i]
+
+		rel := layer.relative(l.normalize(abs))
+		for _, sec := range sortedSections(layer.cfg.SecToPat) {
+			if _, found := layer.cfg.SChecks[sec][name]; found && layer.cfg.SecToPat[sec].Match(rel) {
+				return fmt.Sprintf("[%s] (%s)", sec, l.nestedLabel(layer))
+			}
+		}
+
+		if _, found := layer.cfg.GChecks[name]; found {
+			return fmt.Sprintf("[*] (%s)", l.nestedLabel(layer))
+		}
+	}
+
+	fp := l.normalize(path)
+	for _, sec := range sortedSections(l.Manager.Config.SecToPat) {
+		if _, found := l.Manager.Config.SChecks[sec][name]; found && l.Manager.Config.SecToPat[sec].Match(fp) {
+			return "[" + sec + "]"
+		}
+	}
+
+	return "[*]"
+}
+
+// nestedLabel is the path of a nested configuration file, relative to the
+// root configuration.
+func (l *Linter) nestedLabel(layer nestedConfig) string {
+	if rel, err := filepath.Rel(l.rootDir(), layer.cfg.Path); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return layer.cfg.Path
+}
+
+func sortedSections(secToPat map[string]glob.Glob) []string {
+	sections := []string{}
+	for sec := range secToPat {
+		sections = append(sections, sec)
+	}
+	sort.Strings(sections)
+	return sections
+}
@@ -0,0 +1,49 @@
+package lint
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	dir := t.TempDir()
+
+	linter := testLinter(t, dir, map[string]string{
+		"styles/Test/Low.yml": "extends: existence\nmessage: \"Remove '%s'.\"\nlevel: suggestion\ntokens:\n  - low\n",
+		"sub/.vale.ini":       "[*.md]\nTest.Very = NO\n",
+		"sub/a.md":            "This is very good.\n",
+	})
+	linter.Manager.Config.MinAlertLevel = 1
+
+	exp, err := linter.Explain(filepath.Join(dir, "sub", "a.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(exp.Sections) != 1 || exp.Sections[0] != "[*.md] (sub/.vale.ini)" {
+		t.Errorf("unexpected sections: %v", exp.Sections)
+	}
+
+	expected := map[string]RuleStatus{
+		"Test.Low": {
+			Runs: false, Reason: "'suggestion' is below MinAlertLevel ('warning')"},
+		"Test.Very": {
+			Runs: false, Reason: "disabled in [*.md] (sub/.vale.ini)"},
+		"Vale.Spelling": {
+			Runs: false, Reason: "'Vale' isn't in BasedOnStyles"},
+	}
+
+	for _, r := range exp.Rules {
+		if want, found := expected[r.Name]; found {
+			if r.Runs != want.Runs || r.Reason != want.Reason {
+				t.Errorf("%s: expected (%v, %q), got (%v, %q)",
+					r.Name, want.Runs, want.Reason, r.Runs, r.Reason)
+			}
+			delete(expected, r.Name)
+		}
+	}
+
+	for name := range expected {
+		t.Errorf("missing rule '%s'", name)
+	}
+}
@@ -28,23 +28,35 @@ The package is split into core linting logic (this file), source code
 package lint
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/errata-ai/vale/v2/check"
 	"github.com/errata-ai/vale/v2/config"
 	"github.com/errata-ai/vale/v2/core"
+	"github.com/gobwas/glob"
 	"github.com/remeh/sizedwaitgroup"
 )
 
 // A Linter lints a File.
 type Linter struct {
 	Manager *check.Manager
+	Timings *Timings // per-file and per-rule timings (if requested)
 
+	cache     *cache
+	dir       string // what relative paths are relative to (see `LintContentFrom`)
+	exclude   []glob.Glob
+	nested    map[string]*config.Config
+	pool      chan struct{}
 	seen      map[string]bool
 	glob      *core.Glob
 	nonGlobal bool
@@ -62,16 +74,69 @@ func NewLinter(cfg *config.Config) (*Linter, error) {
 	globalStyles := len(cfg.GBaseStyles)
 	globalChecks := len(cfg.GChecks)
 
+	var timings *Timings
+	if cfg.Timings || cfg.Profile {
+		timings = newTimings(cfg.Profile)
+	}
+
+	exclude := []glob.Glob{}
+	for _, pat := range cfg.Exclude {
+		g, gerr := glob.Compile(pat)
+		if gerr != nil {
+			return nil, core.NewE201FromTarget(
+				fmt.Sprintf("The glob pattern '%s' could not be compiled.", pat),
+				pat,
+				cfg.Path)
+		}
+		exclude = append(exclude, g)
+	}
+
 	return &Linter{
 		Manager: mgr,
+		Timings: timings,
 
+		exclude:   exclude,
+		pool:      make(chan struct{}, concurrency(cfg)),
 		nonGlobal: globalStyles+globalChecks == 0}, err
 }
 
+// concurrency is the number of files (and rules) to process in parallel.
+func concurrency(cfg *config.Config) int {
+	if cfg.Concurrency > 0 {
+		return cfg.Concurrency
+	}
+	return runtime.NumCPU()
+}
+
+// workers is the number of files (and rules) to process in parallel.
+//
+// NOTE: A Linter that wasn't created by `NewLinter` doesn't have a pool, so
+// it falls back to the configured concurrency.
+func (l *Linter) workers() int {
+	if l.pool == nil {
+		return concurrency(l.Manager.Config)
+	}
+	return cap(l.pool)
+}
+
+// acquire takes a slot in the pool, blocking until one is available.
+func (l *Linter) acquire() {
+	if l.pool != nil {
+		l.pool <- struct{}{}
+	}
+}
+
+// release gives up a slot taken by `acquire`.
+func (l *Linter) release() {
+	if l.pool != nil {
+		<-l.pool
+	}
+}
+
 // LintString src according to its format.
 func (l *Linter) LintString(src string) ([]*core.File, error) {
 	linted := l.lintFile(src)
-	return []*core.File{linted.file}, linted.err
+	return []*core.File{linted.file}, l.fail(linted)
 }
 
 // Lint src according to its format.
@@ -81,22 +146,22 @@ func (l *Linter) Lint(input []string, pat string) ([]*core.File, error) {
 	done := make(chan core.File)
 	defer close(done)
 
-	if err := l.setupContent(); err != nil {
+	gp, err := core.NewGlob(pat)
+	if err != nil {
 		return linted, err
 	}
+	l.glob = &gp
 
-	gp, err := core.NewGlob(pat)
-	if err != nil {
+	if err = l.prepare(input); err != nil {
 		return linted, err
 	}
 
-	l.glob = &gp
 	for _, src := range input {
 		filesChan, errChan := l.lintFiles(done, src)
 
 		for result := range filesChan {
-			if result.err != nil {
-				return linted, result.err
+			if err := l.fail(result); err != nil {
+				return linted, err
 			} else if l.Manager.Config.Normalize {
 				result.file.Path = filepath.ToSlash(result.file.Path)
 			}
@@ -111,6 +176,125 @@ func (l *Linter) Lint(input []string, pat string) ([]*core.File, error) {
 	return linted, nil
 }
 
+// LintFiles lints each of the given files.
+//
+// Unlike `Lint`, there's no directory walk (and, therefore, no glob pattern
+// or ignore files): every path is expected to be a file, which makes this
+// suitable for long, pre-computed lists of files (see `--files-from`).
+func (l *Linter) LintFiles(paths []string) ([]*core.File, error) {
+	return l.lintEach(paths, func(i int) lintResult {
+		return l.lintFile(paths[i])
+	})
+}
+
+// LintContent lints each of the given contents as if it were stored at the
+// corresponding path.
+//
+// The paths, which don't need to exist, determine each file's format and
+// configuration. This allows us to lint content that isn't in the working
+// tree (such as the blobs in Git's index; see `--staged`).
+func (l *Linter) LintContent(paths, contents []string) ([]*core.File, error) {
+	if len(paths) != len(contents) {
+		return []*core.File{}, core.NewE100(
+			"LintContent",
+			errors.New("the number of paths and contents must match"))
+	}
+	return l.LintContentFrom("", paths, func(i int) (string, error) {
+		return contents[i], nil
+	})
+}
+
+// LintContentFrom is like `LintContent`, but each file's content is loaded
+// (by read, which is given its index in paths) only once it's about to be
+// linted -- so nothing is read for skipped files, and we don't need to hold
+// every file in memory at once.
+//
+// Relative paths are relative to dir (or, if it's empty, the current
+// directory) rather than the current directory. This is what section globs
+// are matched against, which allows us to lint the paths stored in a Git
+// repository (see `--rev`) as if we were at its root.
+func (l *Linter) LintContentFrom(dir string, paths []string, read func(i int) (string, error)) ([]*core.File, error) {
+	l.dir = dir
+	defer func() { l.dir = "" }()
+
+	return l.lintEach(paths, func(i int) lintResult {
+		content, err := read(i)
+		if err != nil {
+			return lintResult{err: err}
+		}
+		return l.lintContent(paths[i], content)
+	})
+}
+
+// lintEach runs lint for every (non-skipped) path, returning the results in
+// the same order as paths.
+func (l *Linter) lintEach(paths []string, lint func(i int) lintResult) ([]*core.File, error) {
+	l.glob = nil
+	if err := l.prepare(paths); err != nil {
+		return []*core.File{}, err
+	}
+
+	results := make([]lintResult, len(paths))
+
+	wg := sizedwaitgroup.New(l.workers())
+	for i, fp := range paths {
+		if l.skip(fp) {
+			continue
+		}
+
+		wg.Add()
+		go func(i int) {
+			defer wg.Done()
+			results[i] = lint(i)
+		}(i)
+	}
+	wg.Wait()
+
+	linted := []*core.File{}
+	for _, result := range results {
+		if err := l.fail(result); err != nil {
+			return linted, err
+		} else if result.file == nil {
+			// The file was skipped.
+			continue
+		} else if l.Manager.Config.Normalize {
+			result.file.Path = filepath.ToSlash(result.file.Path)
+		}
+		linted = append(linted, result.file)
+	}
+
+	return linted, nil
+}
+
+// fail handles a result's error (if any): in `KeepGoing` mode, it's recorded
+// on the file (which is still reported) so that the rest of the files can be
+// linted; otherwise, it's returned.
+func (l *Linter) fail(result lintResult) error {
+	if result.err == nil {
+		return nil
+	} else if !l.Manager.Config.KeepGoing || result.file == nil {
+		return result.err
+	}
+	result.file.Error = core.ErrorMessage(result.err)
+	return nil
+}
+
+// prepare handles the set up that's shared by `Lint`, `LintFiles`, and
+// `LintContent`.
+func (l *Linter) prepare(input []string) error {
+	if err := l.setupContent(); err != nil {
+		return err
+	} else if err = l.loadNested(input); err != nil {
+		return err
+	}
+
+	if l.cache == nil && !l.Manager.Config.NoCache {
+		l.cache = newCache(l.Manager.Config)
+	}
+
+	return nil
+}
+
 // lintFiles walks the `root` directory, creating a new goroutine to lint any
 // file that matches the given glob pattern.
 func (l *Linter) lintFiles(done <-chan core.File, root string) (<-chan lintResult, <-chan error) {
@@ -118,11 +302,17 @@ func (l *Linter) lintFiles(done <-chan core.File, root string) (<-chan lintResul
 	errChan := make(chan error, 1)
 
 	go func() {
-		wg := sizedwaitgroup.New(5)
+		wg := sizedwaitgroup.New(l.workers())
+		ignorer := core.NewIgnorer(root)
 
 		err := filepath.Walk(root, func(fp string, fi os.FileInfo, err error) error {
 			if fi.IsDir() && core.ShouldIgnoreDirectory(fi.Name()) {
 				return filepath.SkipDir
+			} else if err == nil && fp != root && l.ignored(ignorer, fp, fi.IsDir()) {
+				if fi.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
 			} else if err != nil || fi.IsDir() || l.skip(fp) {
 				return nil
 			}
@@ -160,19 +350,62 @@ func (l *Linter) lintFiles(done <-chan core.File, root string) (<-chan lintResul
 // lintFile creates a new `File` from the path `src` and selects a linter based
 // on its format.
 func (l *Linter) lintFile(src string) lintResult {
-	var err error
+	start := time.Now()
 
 	file, err := core.NewFile(src, l.Manager.Config)
 	if err != nil {
-		return lintResult{err: err}
-	} else if len(file.Checks) == 0 && len(file.BaseStyles) == 0 {
+		return lintResult{file: file, err: err}
+	}
+
+	return l.lint(file, start)
+}
+
+// lintContent creates a new `File` from the given content, using `path` to
+// determine its format.
+func (l *Linter) lintContent(path, content string) lintResult {
+	start := time.Now()
+
+	file, err := core.NewFileFromContent(path, content, l.Manager.Config)
+	if err != nil {
+		return lintResult{file: file, err: err}
+	}
+
+	return l.lint(file, start)
+}
+
+// lint selects a linter for file based on its format.
+func (l *Linter) lint(file *core.File, start time.Time) lintResult {
+	var err error
+
+	if l.Timings != nil {
+		defer func() { l.Timings.addFile(file.Path, time.Since(start)) }()
+		if l.Timings.profile {
+			file.Matched = l.Timings.addMatch
+		}
+	}
+
+	l.applyNested(file)
+
+	path := l.abs(file.Path)
+	layers := l.layers(path)
+
+	if len(file.Checks) == 0 && len(file.BaseStyles) == 0 {
 		if len(l.Manager.Config.GBaseStyles) == 0 && len(l.Manager.Config.GChecks) == 0 {
 			// There's nothing to do; bail early.
 			return lintResult{file: file}
 		}
 	}
 
-	if file.Format == "markup" && !l.Manager.Config.Simple {
+	if l.cache != nil {
+		if alerts, found := l.cache.get(file, path, layers); found {
+			file.Alerts = alerts
+			return lintResult{file: file}
+		}
+	}
+
+	if file.Command != "" && !l.Manager.Config.Simple {
+		err = l.lintCommand(file)
+	} else if file.Format == "markup" && !l.Manager.Config.Simple {
 		switch file.NormedExt {
 		case ".adoc":
 			err = l.lintADoc(file)
@@ -193,6 +426,15 @@ func (l *Linter) lintFile(src string) lintResult {
 		l.lintLines(file)
 	}
 
+	if err == nil && file.Error != "" {
+		// One of the rules failed (see `lintBlock`).
+		err = errors.New(file.Error)
+	} else if l.cache != nil && err == nil {
+		l.cache.put(file, path, layers)
+	}
+
+	// A failure (such as a `conversionError`) is either recorded on the
+	// file or stops the run, depending on `KeepGoing` (see `fail`).
 	return lintResult{file, err}
 }
 
@@ -241,37 +483,72 @@ func (l *Linter) lintBlock(f *core.File, blk core.Block, lines, pad int, lookup
 
 	f.ChkToCtx = make(map[string]string)
 
-	results := make(chan core.Alert)
-	for name, chk := range l.Manager.Rules() {
-		if !l.shouldRun(name, f, chk, blk) {
-			continue
+	names := []string{}
+	for name := range l.Manager.Rules() {
+		if l.shouldRun(name, f, l.rule(name, f), blk) {
+			names = append(names, name)
 		}
+	}
+	sort.Strings(names)
+
+	// Each rule writes to its own slot, which means that we don't have to
+	// wait on any other rule to finish before releasing our spot in the pool.
+	results := make([][]core.Alert, len(names))
+	failures := make([]error, len(names))
+	for i, name := range names {
+		l.acquire()
 
 		wg.Add(1)
-		go func(txt, name string, f *core.File, chk check.Rule) {
+		go func(i int, txt, name string, f *core.File, chk check.Rule) {
+			defer func() {
+				l.release()
+				wg.Done()
+			}()
+
+			start := time.Now()
+
+			alerts, err := chk.Run(txt, f)
+			if err != nil {
+				failures[i] = err
+			}
+
 			info := chk.Fields()
-			for _, a := range chk.Run(txt, f) {
+			for _, a := range alerts {
 				core.FormatAlert(&a, info.Limit, info.Level, name)
-				results <- a
+				a.Severity = f.Level(name, a.Severity)
+				results[i] = append(results[i], a)
+			}
+
+			if l.Timings != nil {
+				l.Timings.addRule(name, time.Since(start), len(results[i]))
+				l.Timings.addPattern(name, chk.Pattern())
 			}
-			wg.Done()
-		}(blk.Text, name, f, chk)
+		}(i, blk.Text, name, f, l.rule(name, f))
 	}
+	wg.Wait()
 
-	go func() {
-		wg.Wait()
-		close(results)
-	}()
+	for i, err := range failures {
+		if err != nil && f.Error == "" {
+			// A rule that can fail, such as a plugin, is reported on the file
+			// (like a failed conversion) rather than stopping the run.
+			f.Error = fmt.Sprintf("'%s' failed: %v", names[i], err)
+		}
+	}
 
-	for a := range results {
-		f.AddAlert(a, blk, lines, pad, lookup)
+	for _, alerts := range results {
+		for _, a := range alerts {
+			f.AddAlert(a, blk, lines, pad, lookup)
+		}
 	}
 }
 
-func (l *Linter) shouldRun(name string, f *core.File, chk check.Rule, blk core.Block) bool {
-	min := l.Manager.Config.MinAlertLevel
-	run := false
+// rule returns the named rule as configured for f, which may override some
+// of its options (see `check.Manager.Rule`).
+func (l *Linter) rule(name string, f *core.File) check.Rule {
+	return l.Manager.Rule(name, f.Options[name])
+}
 
+func (l *Linter) shouldRun(name string, f *core.File, chk check.Rule, blk core.Block) bool {
 	details := chk.Fields()
 	if strings.Count(name, ".") > 1 {
 		// NOTE: This fixes the loading issue with consistency checks.
@@ -284,41 +561,74 @@ func (l *Linter) shouldRun(name string, f *core.File, chk check.Rule, blk core.B
 	// It has been disabled via an in-text comment.
 	if f.QueryComments(name) {
 		return false
-	} else if core.LevelToInt[details.Level] < min {
-		return false
 	} else if !blk.Scope.ContainsString(details.Scope) {
 		return false
 	}
 
+	return l.status(name, f, details).runs()
+}
+
+// A ruleStatus is the reason that a rule is (or isn't) run for a file.
+type ruleStatus int
+
+const (
+	belowMinLevel   ruleStatus = iota // its level is below MinAlertLevel
+	sectionDisabled                   // it's disabled for the file's section
+	sectionEnabled                    // it's enabled for the file's section
+	globalDisabled                    // it's disabled for all files
+	globalEnabled                     // it's enabled for all files
+	inBaseStyle                       // its style is one of BasedOnStyles
+	notInBaseStyle                    // its style isn't one of BasedOnStyles
+)
+
+func (s ruleStatus) runs() bool {
+	return s == sectionEnabled || s == globalEnabled || s == inBaseStyle
+}
+
+// status determines if (and why) the rule name applies to f.
+func (l *Linter) status(name string, f *core.File, details check.Definition) ruleStatus {
+	if core.LevelToInt[f.Level(name, details.Level)] < l.Manager.Config.MinAlertLevel {
+		return belowMinLevel
+	}
+
 	// Has the check been disabled for this extension?
-	if val, ok := f.Checks[name]; ok && !run {
+	if val, ok := f.Checks[name]; ok {
 		if !val {
-			return false
+			return sectionDisabled
 		}
-		run = true
+		return sectionEnabled
 	}
 
 	// Has the check been disabled for all extensions?
-	if val, ok := l.Manager.Config.GChecks[name]; ok && !run {
+	if val, ok := l.Manager.Config.GChecks[name]; ok {
 		if !val {
-			return false
+			return globalDisabled
 		}
-		run = true
+		return globalEnabled
 	}
 
 	style := strings.Split(name, ".")[0]
-	if !run && !core.StringInSlice(style, f.BaseStyles) {
-		return false
+	if !core.StringInSlice(style, f.BaseStyles) {
+		return notInBaseStyle
 	}
 
-	return true
+	return inBaseStyle
 }
 
 // setupContent handles any necessary building, compiling, or pre-processing.
 func (l *Linter) setupContent() error {
 	if l.Manager.Config.SphinxAuto != "" {
+		// Sphinx builds can take a long time, so they're only limited by an
+		// explicit `ProcessTimeout`.
+		ctx, cancel := l.processContext(0)
+		defer cancel()
+
 		parts := strings.Split(l.Manager.Config.SphinxAuto, " ")
-		return exec.Command(parts[0], parts[1:]...).Run()
+		if err := exec.CommandContext(ctx, parts[0], parts[1:]...).Run(); ctx.Err() == context.DeadlineExceeded {
+			return core.NewE100("SphinxAutoBuild", l.timedOut(0))
+		} else if err != nil {
+			return core.NewE100("SphinxAutoBuild", err)
+		}
 	}
 	return nil
 }
@@ -330,6 +640,39 @@ func (l *Linter) match(s string) bool {
 	return l.glob.Match(s)
 }
 
+// ignored determines if fp has been excluded by an ignore file (see
+// `core.IgnoreFiles`) or the `Exclude` setting.
+//
+// `Exclude` patterns are matched against paths relative to the directory
+// that holds the root configuration.
+func (l *Linter) ignored(ig *core.Ignorer, fp string, isDir bool) bool {
+	if ig.Ignored(fp, isDir) {
+		return true
+	} else if len(l.exclude) == 0 {
+		return false
+	}
+
+	// Without a root configuration, we fall back to the current directory.
+	base := l.rootDir()
+	if base == "" {
+		base = absPath(".")
+	}
+
+	rel := l.abs(fp)
+	if r, err := filepath.Rel(base, rel); err == nil {
+		rel = r
+	}
+	rel = filepath.ToSlash(rel)
+
+	for _, pat := range l.exclude {
+		if pat.Match(rel) || (isDir && pat.Match(rel+"/")) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (l *Linter) skip(fp string) bool {
 	var ext string
 
@@ -348,7 +691,7 @@ func (l *Linter) skip(fp string) bool {
 				return false
 			}
 		}
-		return true
+		return !l.matchesNested(fp)
 	}
 
 	return false
@@ -1,12 +1,21 @@
 package lint
 
 import (
+	"errors"
+	"fmt"
 	"path/filepath"
+	"reflect"
 	"regexp"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/errata-ai/vale/v2/check"
 	"github.com/errata-ai/vale/v2/config"
+	"github.com/errata-ai/vale/v2/core"
+	"github.com/errata-ai/vale/v2/source"
+	"github.com/gobwas/glob"
 )
 
 func TestGenderBias(t *testing.T) {
@@ -47,6 +56,503 @@ func TestGenderBias(t *testing.T) {
 	}
 }
 
+func TestLintFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	linter := testLinter(t, dir, map[string]string{
+		"b.md":  "This is very good.\n",
+		"a.txt": "This is very, very good.\n",
+	})
+
+	// The glob pattern from a previous walk doesn't apply.
+	if _, err := linter.Lint([]string{dir}, "*.md"); err != nil {
+		t.Fatal(err)
+	}
+
+	paths := []string{filepath.Join(dir, "b.md"), filepath.Join(dir, "a.txt")}
+	linted, err := linter.LintFiles(paths)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(linted) != 2 {
+		t.Fatalf("expected 2 files, got %d", len(linted))
+	}
+
+	// The results are in the same order as the input.
+	for i, expected := range []int{1, 2} {
+		if linted[i].Path != paths[i] || len(linted[i].Alerts) != expected {
+			t.Errorf("expected %d alert(s) for %s, got %d for %s",
+				expected, paths[i], len(linted[i].Alerts), linted[i].Path)
+		}
+	}
+}
+
+func TestLintContent(t *testing.T) {
+	dir := t.TempDir()
+
+	linter := testLinter(t, dir, map[string]string{
+		"a.md": "This is fine.\n",
+	})
+
+	// The content, rather than the file on disk, is what gets linted; the
+	// path determines its format (and configuration).
+	paths := []string{filepath.Join(dir, "a.md"), filepath.Join(dir, "b.md")}
+	contents := []string{"This is\n\nvery `very` good.\n", "Fine.\n"}
+
+	linted, err := linter.LintContent(paths, contents)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(linted) != 2 {
+		t.Fatalf("expected 2 files, got %d", len(linted))
+	}
+
+	alerts := linted[0].Alerts
+	if linted[0].NormedExt != ".md" || len(alerts) != 1 || alerts[0].Line != 3 {
+		t.Errorf("expected 1 alert on line 3 of a Markdown file, got %v", alerts)
+	}
+
+	// The path doesn't need to exist.
+	linted, err = linter.LintContent([]string{filepath.Join(dir, "c.txt")}, []string{"`very`"})
+	if err != nil {
+		t.Fatal(err)
+	} else if len(linted) != 1 || len(linted[0].Alerts) != 1 {
+		t.Errorf("expected 1 file with 1 alert, got %v", linted)
+	}
+
+	if _, err = linter.LintContent(paths, contents[:1]); err == nil {
+		t.Error("expected an error for mismatched paths and contents")
+	}
+}
+
+func TestLintContentFrom(t *testing.T) {
+	dir := t.TempDir()
+
+	writeFiles(t, dir, baseFiles)
+	writeFiles(t, dir, map[string]string{
+		".vale.ini":          "StylesPath = styles\n\n[docs/*.md]\nBasedOnStyles = Test\n",
+		"docs/sub/.vale.ini": "[*.md]\nTest.Very = NO\n",
+	})
+
+	cfg, _ := config.New()
+	cfg.Path = filepath.Join(dir, ".vale.ini")
+	cfg.NoCache = true
+	if err := source.From("ini", cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	linter, err := NewLinter(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The paths are relative to dir, not the current directory, for both the
+	// root configuration's sections and the nested configurations.
+	paths := []string{
+		filepath.Join("docs", "a.md"),
+		filepath.Join("docs", "sub", "b.md"),
+		"c.md",
+		"d.png",
+	}
+
+	var mu sync.Mutex
+	read := []string{}
+
+	linted, err := linter.LintContentFrom(dir, paths, func(i int) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		read = append(read, paths[i])
+		return "This is very good.\n", nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Files that are skipped -- because of their format or because no
+	// section applies to them -- aren't read.
+	if len(read) != 2 || len(linted) != 2 {
+		t.Fatalf("expected 2 files to be read and linted, got %v and %d", read, len(linted))
+	}
+
+	for i, expected := range []int{1, 0} {
+		if linted[i].Path != paths[i] || len(linted[i].Alerts) != expected {
+			t.Errorf("expected %d alert(s) for %s, got %v for %s",
+				expected, paths[i], linted[i].Alerts, linted[i].Path)
+		}
+	}
+
+	if _, err = linter.LintContentFrom(dir, paths[:1], func(i int) (string, error) {
+		return "", errors.New("unreadable")
+	}); err == nil {
+		t.Error("expected an error for an unreadable file")
+	}
+}
+
+func TestRuleOptions(t *testing.T) {
+	dir := t.TempDir()
+
+	writeFiles(t, dir, baseFiles)
+	writeFiles(t, dir, map[string]string{
+		".vale.ini": "StylesPath = styles\n\n[*]\nBasedOnStyles = Test\nTest.Very.tokens = [good]\n\n[**/api/*.md]\nTest.Very.level = error\n",
+	})
+
+	cfg, _ := config.New()
+	cfg.Path = filepath.Join(dir, ".vale.ini")
+	cfg.NoCache = true
+	if err := source.From("ini", cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	linter, err := NewLinter(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	paths := []string{filepath.Join(dir, "a.md"), filepath.Join(dir, "api", "b.md")}
+	content := "This is very good.\n"
+
+	linted, err := linter.LintContent(paths, []string{content, content})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The global options apply everywhere, while the section's options are
+	// applied on top of them.
+	for i, level := range []string{"warning", "error"} {
+		alerts := []core.Alert{}
+		for _, a := range linted[i].Alerts {
+			if a.Check == "Test.Very" {
+				alerts = append(alerts, a)
+			}
+		}
+		if len(alerts) != 1 || alerts[0].Match != "good" || alerts[0].Severity != level {
+			t.Errorf("%s: expected 1 %s for 'good', got %v", paths[i], level, alerts)
+		}
+	}
+}
+
+func TestCommand(t *testing.T) {
+	if core.Which([]string{"cat"}) == "" {
+		t.Skip("cat not found")
+	}
+	dir := t.TempDir()
+
+	linter := testLinter(t, dir, map[string]string{
+		"a.dsl": "Some `very` code.\n\nThis is very good.\n",
+	})
+
+	path := filepath.Join(dir, "a.dsl")
+	linter.Manager.Config.Commands[path] = "cat"
+	linter.Manager.Config.Parsers[path] = "markdown"
+	linter.Manager.Config.SecToPat[path] = glob.MustCompile(path)
+
+	linted, err := linter.LintFiles([]string{path})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The output is parsed as Markdown, so the code span is skipped, but the
+	// alerts are reported against the original file.
+	alerts := linted[0].Alerts
+	if len(alerts) != 1 || alerts[0].Line != 3 || alerts[0].Span[0] != 9 {
+		t.Errorf("expected 1 alert at 3:9, got %v", alerts)
+	}
+}
+
+func TestConversionError(t *testing.T) {
+	if core.Which([]string{"cat"}) == "" || core.Which([]string{"sleep"}) == "" {
+		t.Skip("cat or sleep not found")
+	}
+	dir := t.TempDir()
+
+	linter := testLinter(t, dir, map[string]string{
+		"a.dsl": "This is very good.\n",
+		"b.dsl": "This is very good.\n",
+		"c.dsl": "This is very good.\n",
+	})
+
+	cfg := linter.Manager.Config
+	cfg.Timeout = 1
+	for name, cmd := range map[string]string{"a.dsl": "cat", "b.dsl": "sleep 5", "c.dsl": "cat --bad-flag"} {
+		path := filepath.Join(dir, name)
+		cfg.Commands[path] = cmd
+		cfg.Parsers[path] = "markdown"
+		cfg.SecToPat[path] = glob.MustCompile(path)
+	}
+
+	if _, err := linter.Lint([]string{dir}, "*.dsl"); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	cfg.KeepGoing = true
+	linted, err := linter.Lint([]string{dir}, "*.dsl")
+	if err != nil {
+		t.Fatal(err)
+	} else if len(linted) != 3 {
+		t.Fatalf("expected 3 files, got %d", len(linted))
+	}
+
+	// A failing (or hung) converter only affects its own file.
+	for _, f := range linted {
+		switch name := filepath.Base(f.Path); name {
+		case "a.dsl":
+			if len(f.Alerts) != 1 || f.Error != "" {
+				t.Errorf("%s: expected 1 alert, got %v (%q)", name, f.Alerts, f.Error)
+			}
+		case "b.dsl":
+			if !strings.Contains(f.Error, "timed out") {
+				t.Errorf("%s: expected a timeout, got %q", name, f.Error)
+			}
+		case "c.dsl":
+			if !strings.HasPrefix(f.Error, "'cat' failed") {
+				t.Errorf("%s: expected a failure, got %q", name, f.Error)
+			}
+		}
+	}
+}
+
+func TestSphinxAutoTimeout(t *testing.T) {
+	if core.Which([]string{"sleep"}) == "" {
+		t.Skip("sleep not found")
+	}
+	dir := t.TempDir()
+
+	linter := testLinter(t, dir, map[string]string{
+		"a.txt": "This is very good.\n",
+	})
+	linter.Manager.Config.Timeout = 1
+	linter.Manager.Config.SphinxAuto = "sleep 5"
+
+	_, err := linter.Lint([]string{dir}, "*")
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("expected a timeout, got %v", err)
+	}
+
+	// Without an explicit `ProcessTimeout`, a Sphinx build has no deadline
+	// but a converter does.
+	linter.Manager.Config.Timeout = 0
+	for def, expected := range map[int]bool{0: false, converterTimeout: true} {
+		ctx, cancel := linter.processContext(def)
+		if _, found := ctx.Deadline(); found != expected {
+			t.Errorf("processContext(%d): expected a deadline = %v", def, expected)
+		}
+		cancel()
+	}
+}
+
+// failingRule is a rule that always fails, like a plugin whose process has
+// died.
+type failingRule struct{}
+
+func (r failingRule) Run(text string, file *core.File) ([]core.Alert, error) {
+	return nil, errors.New("the plugin exited unexpectedly")
+}
+
+func (r failingRule) Fields() check.Definition {
+	return check.Definition{Name: "Test.Fail", Scope: "text", Level: "error"}
+}
+
+func (r failingRule) Pattern() string {
+	return ""
+}
+
+func TestRuleFailure(t *testing.T) {
+	dir := t.TempDir()
+
+	linter := testLinter(t, dir, map[string]string{
+		"a.txt": "This is very good.\n",
+	})
+	if err := linter.Manager.AddRule("Test.Fail", failingRule{}); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "a.txt")
+	if _, err := linter.LintFiles([]string{path}); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	linter.Manager.Config.KeepGoing = true
+	linted, err := linter.LintFiles([]string{path})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The other rules still run.
+	f := linted[0]
+	if len(f.Alerts) != 1 || f.Error != "'Test.Fail' failed: the plugin exited unexpectedly" {
+		t.Errorf("expected 1 alert and a failure, got %v (%q)", f.Alerts, f.Error)
+	}
+}
+
+func TestKeepGoing(t *testing.T) {
+	dir := t.TempDir()
+
+	linter := testLinter(t, dir, map[string]string{
+		"a.md": "This is very good.\n",
+		// There's no XSLT transform (or, possibly, `xsltproc`).
+		"b.xml": "<p>This is very good.</p>\n",
+	})
+	linter.Manager.Config.InExt = ".txt"
+
+	if _, err := linter.Lint([]string{dir}, "*.{md,xml}"); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	linter.Manager.Config.KeepGoing = true
+	linted, err := linter.Lint([]string{dir}, "*.{md,xml}")
+	if err != nil {
+		t.Fatal(err)
+	} else if len(linted) != 2 {
+		t.Fatalf("expected 2 files, got %d", len(linted))
+	}
+
+	for _, f := range linted {
+		if filepath.Ext(f.Path) == ".md" && (len(f.Alerts) != 1 || f.Error != "") {
+			t.Errorf("%s: expected 1 alert, got %v (%q)", f.Path, f.Alerts, f.Error)
+		} else if filepath.Ext(f.Path) == ".xml" && (f.Error == "" || strings.Contains(f.Error, "E100")) {
+			t.Errorf("%s: expected a plain error, got %q", f.Path, f.Error)
+		}
+	}
+}
+
+func TestRST(t *testing.T) {
+	dir := t.TempDir()
+
+	linter := testLinter(t, dir, map[string]string{
+		"styles/Test/Cell.yml": "extends: existence\nmessage: \"Remove '%s'.\"\nscope: table.cell\ntokens:\n  - nice\n",
+		"a.rst": strings.Join([]string{
+			"A very good title",
+			"=================",
+			"",
+			"This is *very* good, but ``very`` is code.",
+			"",
+			".. code-block:: python",
+			"",
+			"   very = 1",
+			"",
+			"+--------+--------------+",
+			"| Header | Other        |",
+			"+========+==============+",
+			"| nice   | so very nice |",
+			"+--------+--------------+",
+			"",
+			"- An item that's very good.",
+			"",
+		}, "\n"),
+	})
+	linter.Manager.Config.InExt = ".txt"
+
+	// We don't need `rst2html` (or Python) to lint reStructuredText.
+	linted, err := linter.LintFiles([]string{filepath.Join(dir, "a.rst")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"13:15:Test.Very",
+		"13:20:Test.Cell",
+		"13:3:Test.Cell",
+		"16:18:Test.Very",
+		"1:3:Test.Very",
+		"4:10:Test.Very",
+	}
+
+	found := []string{}
+	for _, a := range linted[0].Alerts {
+		found = append(found, fmt.Sprintf("%d:%d:%s", a.Line, a.Span[0], a.Check))
+	}
+	sort.Strings(found)
+
+	if !reflect.DeepEqual(found, expected) {
+		t.Errorf("expected %v, got %v", expected, found)
+	}
+}
+
+func TestADoc(t *testing.T) {
+	dir := t.TempDir()
+
+	linter := testLinter(t, dir, map[string]string{
+		"styles/Test/Cell.yml": "extends: existence\nmessage: \"Remove '%s'.\"\nscope: table.cell\ntokens:\n  - nice\n",
+		"attrs.adoc":           ":internal:\n",
+		"a.adoc": strings.Join([]string{
+			"= A very good title",
+			"",
+			"include::attrs.adoc[]",
+			"",
+			"This is *very* good, but `very` is code.",
+			"",
+			"[source,python]",
+			"----",
+			"very = 1",
+			"----",
+			"",
+			"|===",
+			"| Header | Other",
+			"",
+			"| nice | so very nice",
+			"|===",
+			"",
+			"* An item that's very good.",
+			"",
+			"ifdef::internal[]",
+			"A very internal note.",
+			"endif::[]",
+			"",
+			"ifdef::external[]",
+			"A very external note.",
+			"endif::[]",
+			"",
+		}, "\n"),
+	})
+	linter.Manager.Config.InExt = ".txt"
+
+	// We don't need Asciidoctor (or Ruby) to lint AsciiDoc.
+	linted, err := linter.LintFiles([]string{filepath.Join(dir, "a.adoc")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"15:13:Test.Very",
+		"15:18:Test.Cell",
+		"15:3:Test.Cell",
+		"18:18:Test.Very",
+		"1:5:Test.Very",
+		"21:3:Test.Very",
+		"5:10:Test.Very",
+	}
+
+	found := []string{}
+	for _, a := range linted[0].Alerts {
+		found = append(found, fmt.Sprintf("%d:%d:%s", a.Line, a.Span[0], a.Check))
+	}
+	sort.Strings(found)
+
+	if !reflect.DeepEqual(found, expected) {
+		t.Errorf("expected %v, got %v", expected, found)
+	}
+}
+
+func TestLinterWithoutPool(t *testing.T) {
+	cfg, err := config.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.GBaseStyles = []string{"Vale"}
+
+	mgr, err := check.NewManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A Linter that wasn't created by `NewLinter` is still usable.
+	linter := Linter{Manager: mgr}
+	linted, err := linter.LintString("This is is a test.")
+	if err != nil {
+		t.Fatal(err)
+	} else if len(linted[0].Alerts) != 1 {
+		t.Errorf("expected 1 alert, got %v", linted[0].Alerts)
+	}
+}
+
 func benchmarkLint(path string, b *testing.B) {
 	cfg, err := config.New()
 	if err != nil {
@@ -76,3 +582,78 @@ func BenchmarkLintRST(b *testing.B) {
 func BenchmarkLintMD(b *testing.B) {
 	benchmarkLint("../fixtures/benchmarks/bench.md", b)
 }
+
+func TestExclude(t *testing.T) {
+	dir := t.TempDir()
+
+	writeFiles(t, dir, baseFiles)
+	writeFiles(t, dir, map[string]string{
+		".vale.ini":     "StylesPath = styles\n\n[*]\nBasedOnStyles = Test\nExclude = {vendor,build}/**\nExclude = *.txt\n",
+		"a.md":          "This is very good.\n",
+		"a.txt":         "This is very good.\n",
+		"build/b.md":    "This is very good.\n",
+		"vendor/c.md":   "This is very good.\n",
+		"docs/build.md": "This is very good.\n",
+	})
+
+	cfg, _ := config.New()
+	cfg.Path = filepath.Join(dir, ".vale.ini")
+	cfg.NoCache = true
+	if err := source.From("ini", cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	linter, err := NewLinter(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The (absolute) paths are matched relative to the configuration, not
+	// the current directory.
+	linted, err := linter.Lint([]string{dir}, "*.{md,txt}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found := []string{}
+	for _, f := range linted {
+		rel, _ := filepath.Rel(dir, f.Path)
+		found = append(found, filepath.ToSlash(rel))
+	}
+	sort.Strings(found)
+
+	expected := []string{"a.md", "docs/build.md"}
+	if !reflect.DeepEqual(found, expected) {
+		t.Errorf("expected %v, got %v", expected, found)
+	}
+}
+
+func TestFixVocab(t *testing.T) {
+	cfg, _ := config.New()
+	cfg.AcceptedTokens["JavaScript"] = struct{}{}
+	cfg.GBaseStyles = []string{"Vale"}
+	cfg.InExt = ".txt"
+	cfg.NoCache = true
+
+	linter, err := NewLinter(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src := "I love javascript.\n"
+	linted, err := linter.LintString(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// `Vale.Terms` doesn't have an explicit action, so its swap is the fix.
+	fixes := core.FindFixes(src, linted[0].Alerts)
+	if len(fixes) != 1 {
+		t.Fatalf("expected 1 fix, got %v", linted[0].Alerts)
+	}
+
+	expected := "I love JavaScript.\n"
+	if fixed := core.ApplyFixes(src, fixes); fixed != expected {
+		t.Errorf("expected = %q, got = %q", expected, fixed)
+	}
+}
@@ -2,6 +2,7 @@ package lint
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -10,6 +11,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/errata-ai/vale/v2/core"
 	"github.com/gobwas/glob"
@@ -87,6 +89,72 @@ var reExInfo = regexp.MustCompile("`{3,}" + `.+`)
 // HTML configuration.
 var heading = regexp.MustCompile(`^h\d$`)
 
+// A conversionError means that an external converter (such as `asciidoctor`)
+// failed on, or timed out while processing, a particular file.
+//
+// In `KeepGoing` mode, it's reported on the file (see `core.File.Error`)
+// rather than stopping the rest of the files from being linted.
+type conversionError struct {
+	cmd string
+	err error
+}
+
+func (e conversionError) Error() string {
+	return fmt.Sprintf("'%s' failed: %v", e.cmd, e.err)
+}
+
+// convert runs an external converter, writing stdin to it and returning its
+// output.
+//
+// The converter is killed if it hasn't finished after `ProcessTimeout`
+// seconds (or `converterTimeout`, if that hasn't been set).
+func (l Linter) convert(name string, args []string, stdin string) ([]byte, error) {
+	var out, stderr bytes.Buffer
+
+	ctx, cancel := l.processContext(converterTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); ctx.Err() == context.DeadlineExceeded {
+		return nil, conversionError{cmd: filepath.Base(name), err: l.timedOut(converterTimeout)}
+	} else if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			err = errors.New(msg)
+		}
+		return nil, conversionError{cmd: filepath.Base(name), err: err}
+	}
+
+	return out.Bytes(), nil
+}
+
+// converterTimeout is how long, in seconds, we wait for a converter (such as
+// `rst2html`, `asciidoctor`, `xsltproc`, `dita`, or a section's `Command`)
+// if `ProcessTimeout` hasn't been set. It's generous since DITA builds can
+// take several seconds.
+const converterTimeout = 60
+
+// processContext returns the context that an external process runs in, which
+// expires after `ProcessTimeout` seconds -- or def, if that hasn't been set
+// (see `config.Config.ProcessTimeout`).
+func (l Linter) processContext(def int) (context.Context, context.CancelFunc) {
+	timeout := l.Manager.Config.ProcessTimeout(def)
+	if timeout > 0 {
+		return context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	}
+	return context.WithCancel(context.Background())
+}
+
+// timedOut is the error reported for a process that exceeded the deadline
+// given by `processContext(def)`.
+func (l Linter) timedOut(def int) error {
+	return fmt.Errorf(
+		"timed out after %ds (see ProcessTimeout)", l.Manager.Config.ProcessTimeout(def))
+}
+
 func (l Linter) lintHTML(f *core.File) {
 	if l.Manager.Config.Built != "" {
 		l.lintTxtToHTML(f)
@@ -198,25 +266,27 @@ func (l Linter) lintSphinx(f *core.File) error {
 	return nil
 }
 
+// lintRST lints a reStructuredText file with our own parser or, if its
+// `Parser` is "rst2html", by converting it to HTML with Docutils.
 func (l Linter) lintRST(file *core.File) error {
+	if l.Manager.Config.SphinxBuild != "" {
+		return l.lintSphinx(file)
+	} else if file.Parser != "rst2html" {
+		return l.lintRSTNative(file)
+	}
+
 	rst2html := core.Which([]string{"rst2html", "rst2html.py"})
 	python := core.Which([]string{
 		"python", "py", "python.exe", "python3", "python3.exe", "py3"})
 
 	if rst2html == "" || python == "" {
 		return core.NewE100("lintRST", errors.New("rst2html not found"))
-	} else if l.Manager.Config.SphinxBuild != "" {
-		return l.lintSphinx(file)
 	}
 
-	var out bytes.Buffer
-	var cmd *exec.Cmd
-
+	name, args := rst2html, rstArgs
 	if runtime.GOOS == "windows" {
 		// rst2html is executable by default on Windows.
-		cmd = exec.Command(python, append([]string{rst2html}, rstArgs...)...)
-	} else {
-		cmd = exec.Command(rst2html, rstArgs...)
+		name, args = python, append([]string{rst2html}, rstArgs...)
 	}
 
 	s, err := l.prep(file.Content, "\n::\n\n%s\n", "``$1``", ".rst")
@@ -225,14 +295,12 @@ func (l Linter) lintRST(file *core.File) error {
 	}
 	s = reSphinx.ReplaceAllString(s, ".. code::")
 
-	cmd.Stdin = strings.NewReader(reCodeBlock.ReplaceAllString(s, "::"))
-	cmd.Stdout = &out
-
-	if err := cmd.Run(); err != nil {
-		return core.NewE201FromPosition(err.Error(), file.Path, 1)
+	out, err := l.convert(name, args, reCodeBlock.ReplaceAllString(s, "::"))
+	if err != nil {
+		return err
 	}
 
-	html := bytes.Replace(out.Bytes(), []byte("\r"), []byte(""), -1)
+	html := bytes.Replace(out, []byte("\r"), []byte(""), -1)
 	bodyStart := bytes.Index(html, []byte("<body>\n"))
 	if bodyStart < 0 {
 		bodyStart = -7
@@ -249,25 +317,26 @@ func (l Linter) lintRST(file *core.File) error {
 	return nil
 }
 
+// lintADoc lints an AsciiDoc file with our own parser or, if its `Parser` is
+// "asciidoctor", by converting it to HTML with Asciidoctor.
 func (l Linter) lintADoc(f *core.File) error {
-	var out bytes.Buffer
+	if f.Parser != "asciidoctor" {
+		return l.lintADocNative(f)
+	}
 
 	asciidoctor := core.Which([]string{"asciidoctor"})
 	if asciidoctor == "" {
 		return core.NewE100("lintAdoc", errors.New("asciidoctor not found"))
 	}
 
-	cmd := exec.Command(asciidoctor, adocArgs...)
 	s, err := l.prep(f.Content, "\n----\n$1\n----\n", "`$1`", ".adoc")
 	if err != nil {
 		return core.NewE100(f.Path, err)
 	}
 
-	cmd.Stdin = strings.NewReader(s)
-	cmd.Stdout = &out
-
-	if err := cmd.Run(); err != nil {
-		return core.NewE100(f.Path, err)
+	out, err := l.convert(asciidoctor, adocArgs, s)
+	if err != nil {
+		return err
 	}
 
 	// NOTE: Asciidoctor converts "'" to "’".
@@ -278,7 +347,7 @@ func (l Linter) lintADoc(f *core.File) error {
 		"\u2019", "&apos;",
 		"&#8217;", "&apos;",
 		"&rsquo;", "&apos;")
-	input := sanitizer.Replace(out.String())
+	input := sanitizer.Replace(string(out))
 
 	// NOTE: This is required to avoid finding matches in block attributes.
 	//
@@ -290,8 +359,6 @@ func (l Linter) lintADoc(f *core.File) error {
 }
 
 func (l Linter) lintXML(file *core.File) error {
-	var out bytes.Buffer
-
 	xsltproc := core.Which([]string{"xsltproc", "xsltproc.exe"})
 	if xsltproc == "" {
 		return core.NewE100("lintXML", errors.New("xsltproc not found"))
@@ -301,22 +368,47 @@ func (l Linter) lintXML(file *core.File) error {
 			errors.New("no XSLT transform provided"))
 	}
 
-	xsltArgs = append(xsltArgs, []string{file.Transform, "-"}...)
+	args := append(append([]string{}, xsltArgs...), file.Transform, "-")
 
-	cmd := exec.Command(xsltproc, xsltArgs...)
-	cmd.Stdin = strings.NewReader(file.Content)
-	cmd.Stdout = &out
+	out, err := l.convert(xsltproc, args, file.Content)
+	if err != nil {
+		return err
+	}
 
-	if err := cmd.Run(); err != nil {
-		return core.NewE100(file.Path, err)
+	l.lintHTMLTokens(file, out, 0)
+	return nil
+}
+
+// lintCommand lints a file by converting it to HTML or Markdown (see
+// `Parser`) with a user-provided `Command`, which allows us to support
+// formats that we don't natively understand.
+//
+// The file's content is written to the command's stdin and the converted
+// content is read from its stdout.
+func (l Linter) lintCommand(file *core.File) error {
+	parts := strings.Fields(file.Command)
+	if len(parts) == 0 {
+		return core.NewE100("lintCommand", errors.New("empty command"))
+	}
+
+	html, err := l.convert(parts[0], parts[1:], file.Content)
+	if err != nil {
+		return err
 	}
 
-	l.lintHTMLTokens(file, out.Bytes(), 0)
+	if file.Parser == "markdown" {
+		var buf bytes.Buffer
+		if err := goldMd.Convert(html, &buf); err != nil {
+			return core.NewE100(file.Path, err)
+		}
+		html = buf.Bytes()
+	}
+
+	l.lintHTMLTokens(file, html, 0)
 	return nil
 }
 
 func (l Linter) lintDITA(file *core.File) error {
-	var out bytes.Buffer
 	var htmlFile string
 
 	dita := core.Which([]string{"dita", "dita.bat"})
@@ -332,7 +424,7 @@ func (l Linter) lintDITA(file *core.File) error {
 	}
 
 	// FIXME: The `dita` command is *slow* (~4s per file)!
-	cmd := exec.Command(dita, []string{
+	_, err = l.convert(dita, []string{
 		"-i",
 		file.Path,
 		"-f",
@@ -341,11 +433,9 @@ func (l Linter) lintDITA(file *core.File) error {
 		tempDir,
 		"--nav-toc=none",
 		"--outer.control=quiet", // allows DITA files to reference external files, like in conrefs.
-	}...)
-	cmd.Stderr = &out
-
-	if err := cmd.Run(); err != nil {
-		return core.NewE100(file.Path, err)
+	}, "")
+	if err != nil {
+		return err
 	}
 
 	targetFileName := strings.TrimSuffix(filepath.Base(file.Path), filepath.Ext(file.Path)) + ".html"
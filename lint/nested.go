@@ -0,0 +1,259 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/errata-ai/vale/v2/check"
+	"github.com/errata-ai/vale/v2/config"
+	"github.com/errata-ai/vale/v2/core"
+	"github.com/errata-ai/vale/v2/source"
+)
+
+// A nestedConfig is a configuration file stored in a subdirectory of the
+// project. Like an `.editorconfig` file, it applies to every file beneath it
+// and takes precedence over the configuration(s) above it.
+type nestedConfig struct {
+	dir string
+	cfg *config.Config
+}
+
+// loadNested finds the nested configuration files that apply to input: any
+// in (or beneath) the input directories themselves and any between them and
+// the directory that holds the root configuration.
+//
+// This needs to happen before we start linting since every style referenced
+// by a nested configuration has to be loaded up front.
+func (l *Linter) loadNested(input []string) error {
+	l.nested = map[string]*config.Config{}
+
+	root := l.rootDir()
+	if root == "" {
+		// There's no root configuration to build upon.
+		return nil
+	}
+
+	dirs := []string{}
+	for _, src := range input {
+		abs := l.abs(src)
+		if !core.IsDir(abs) {
+			abs = filepath.Dir(abs)
+		} else {
+			ignorer := core.NewIgnorer(abs)
+			err := filepath.Walk(abs, func(fp string, fi os.FileInfo, err error) error {
+				if err != nil || !fi.IsDir() {
+					return nil
+				} else if fp != abs && (core.ShouldIgnoreDirectory(fi.Name()) || l.ignored(ignorer, fp, true)) {
+					return filepath.SkipDir
+				}
+				dirs = append(dirs, fp)
+				return nil
+			})
+			if err != nil {
+				return core.NewE100("loadNested", err)
+			}
+		}
+
+		for dir := abs; isBeneath(dir, root); dir = filepath.Dir(dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+
+	for _, dir := range dirs {
+		if _, found := l.nested[dir]; found || !isBeneath(dir, root) {
+			continue
+		}
+
+		path := source.FindNested(dir)
+		if path == "" {
+			l.nested[dir] = nil
+			continue
+		}
+
+		cfg, err := source.LoadNested(path)
+		if err != nil {
+			return err
+		} else if err = l.Manager.AddConfig(cfg); err != nil {
+			return err
+		}
+		l.nested[dir] = cfg
+	}
+
+	return nil
+}
+
+// layers returns the nested configurations that apply to the file at path,
+// ordered from the outermost to the innermost.
+func (l *Linter) layers(path string) []nestedConfig {
+	found := []nestedConfig{}
+	if len(l.nested) == 0 {
+		return found
+	}
+
+	root := l.rootDir()
+	for dir := filepath.Dir(path); isBeneath(dir, root); dir = filepath.Dir(dir) {
+		if cfg := l.nested[dir]; cfg != nil {
+			found = append([]nestedConfig{{dir: dir, cfg: cfg}}, found...)
+		}
+	}
+
+	return found
+}
+
+// applyNested merges the nested configurations that apply to f over the
+// settings it was assigned by the root configuration.
+func (l *Linter) applyNested(f *core.File) {
+	path := l.abs(f.Path)
+
+	for _, layer := range l.layers(path) {
+		rel := layer.relative(l.normalize(path))
+
+		if len(layer.cfg.GBaseStyles) > 0 {
+			f.BaseStyles = layer.cfg.GBaseStyles
+		}
+		for sec, styles := range layer.cfg.SBaseStyles {
+			if layer.cfg.SecToPat[sec].Match(rel) {
+				f.BaseStyles = styles
+				break
+			}
+		}
+
+		checks := make(map[string]bool)
+		for name, run := range f.Checks {
+			checks[name] = run
+		}
+		for name, run := range layer.cfg.GChecks {
+			checks[name] = run
+		}
+		for sec, smap := range layer.cfg.SChecks {
+			if layer.cfg.SecToPat[sec].Match(rel) {
+				for name, run := range smap {
+					checks[name] = run
+				}
+				break
+			}
+		}
+		f.Checks = checks
+
+		for name, level := range layer.cfg.RuleToLevel {
+			if f.Levels == nil {
+				f.Levels = make(map[string]string)
+			}
+			f.Levels[name] = level
+		}
+
+		for name, sec := range layer.options(rel) {
+			if f.Options == nil {
+				f.Options = make(map[string]string)
+			}
+			f.Options[name] = sec
+		}
+	}
+}
+
+// options returns the section (see `check.NestedSection`) whose rule options
+// apply to the file at rel for each rule that the layer overrides.
+//
+// As in the root configuration, the first (sorted) matching section takes
+// precedence over the global (`[*]`) options.
+func (n nestedConfig) options(rel string) map[string]string {
+	options := make(map[string]string)
+	for name := range n.cfg.RuleOpts["*"] {
+		options[name] = check.NestedSection(n.cfg, "*")
+	}
+
+	sections := []string{}
+	for sec := range n.cfg.RuleOpts {
+		if pat, found := n.cfg.SecToPat[sec]; found && pat.Match(rel) {
+			sections = append(sections, sec)
+		}
+	}
+	sort.Strings(sections)
+
+	matched := make(map[string]bool)
+	for _, sec := range sections {
+		for name := range n.cfg.RuleOpts[sec] {
+			if !matched[name] {
+				options[name] = check.NestedSection(n.cfg, sec)
+				matched[name] = true
+			}
+		}
+	}
+
+	return options
+}
+
+// matchesNested determines if any nested configuration assigns styles or
+// rules to the file at path.
+func (l *Linter) matchesNested(path string) bool {
+	path = l.abs(path)
+	for _, layer := range l.layers(path) {
+		if len(layer.cfg.GBaseStyles)+len(layer.cfg.GChecks) > 0 {
+			return true
+		}
+
+		rel := layer.relative(l.normalize(path))
+		for _, pat := range layer.cfg.SecToPat {
+			if pat.Match(rel) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// relative returns path relative to the layer's directory, which is what its
+// section globs are matched against.
+func (n nestedConfig) relative(path string) string {
+	if rel, err := filepath.Rel(n.dir, path); err == nil {
+		path = rel
+	}
+	return filepath.ToSlash(path)
+}
+
+// normalize replaces path's extension with its associated format, if any.
+func (l *Linter) normalize(path string) string {
+	old := filepath.Ext(path)
+	if normed, found := l.Manager.Config.Formats[strings.Trim(old, ".")]; found {
+		path = path[0:len(path)-len(old)] + "." + normed
+	}
+	return path
+}
+
+// rootDir is the directory that holds the root configuration.
+func (l *Linter) rootDir() string {
+	if l.Manager.Config.Path == "" || !core.FileExists(l.Manager.Config.Path) {
+		return ""
+	}
+	return absPath(filepath.Dir(l.Manager.Config.Path))
+}
+
+// isBeneath determines if dir is a (strict) subdirectory of root.
+func isBeneath(dir, root string) bool {
+	if root == "" {
+		return false
+	}
+	rel, err := filepath.Rel(root, dir)
+	if err != nil || rel == "." {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// abs returns the absolute version of path, which -- if it's relative -- is
+// relative to `l.dir`.
+func (l *Linter) abs(path string) string {
+	if l.dir != "" && !filepath.IsAbs(path) {
+		path = filepath.Join(l.dir, path)
+	}
+	return absPath(path)
+}
+
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
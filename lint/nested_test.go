@@ -0,0 +1,103 @@
+package lint
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNested(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"a.md":                "This is very good.\n",
+		"sub/.vale.ini":       "[*.md]\nTest.Very = error\n\n[*.txt]\nTest.Very = NO\n",
+		"sub/a.md":            "This is very good.\n",
+		"sub/a.txt":           "This is very good.\n",
+		"sub/deep/.vale.yaml": "\"*\":\n  Test.Very: true\n",
+		"sub/deep/a.txt":      "This is very good.\n",
+	}
+	linter := testLinter(t, dir, files)
+
+	linted, err := linter.Lint([]string{dir}, "*.{md,txt}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"a.md":           "warning",
+		"sub/a.md":       "error",
+		"sub/a.txt":      "",
+		"sub/deep/a.txt": "error",
+	}
+	if len(linted) != len(expected) {
+		t.Fatalf("expected %d files, got %d", len(expected), len(linted))
+	}
+
+	for _, f := range linted {
+		rel, _ := filepath.Rel(dir, f.Path)
+		rel = filepath.ToSlash(rel)
+
+		level, found := expected[rel]
+		if !found {
+			t.Errorf("unexpected file '%s'", rel)
+			continue
+		}
+
+		if level == "" && len(f.Alerts) != 0 {
+			t.Errorf("%s: expected no alerts, got %v", rel, f.Alerts)
+		} else if level != "" && (len(f.Alerts) != 1 || f.Alerts[0].Severity != level) {
+			t.Errorf("%s: expected one %s, got %v", rel, level, f.Alerts)
+		}
+	}
+}
+
+func TestNestedRuleOptions(t *testing.T) {
+	dir := t.TempDir()
+
+	linter := testLinter(t, dir, map[string]string{
+		"a.md":          "This is very nice.\n",
+		"sub/.vale.ini": "[*.md]\nTest.Very.level = error\nTest.Very.tokens = [nice]\n",
+		"sub/a.md":      "This is very nice.\n",
+		"sub/a.txt":     "This is very nice.\n",
+	})
+
+	linted, err := linter.Lint([]string{dir}, "*.{md,txt}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"a.md":      "very:warning",
+		"sub/a.md":  "nice:error",
+		"sub/a.txt": "very:warning",
+	}
+	if len(linted) != len(expected) {
+		t.Fatalf("expected %d files, got %d", len(expected), len(linted))
+	}
+
+	for _, f := range linted {
+		rel, _ := filepath.Rel(dir, f.Path)
+		rel = filepath.ToSlash(rel)
+
+		if len(f.Alerts) != 1 {
+			t.Errorf("%s: expected one alert, got %v", rel, f.Alerts)
+		} else if found := f.Alerts[0].Match + ":" + f.Alerts[0].Severity; found != expected[rel] {
+			t.Errorf("%s: expected %s, got %s", rel, expected[rel], found)
+		}
+	}
+}
+
+func TestNestedRuleOptionsTypo(t *testing.T) {
+	dir := t.TempDir()
+
+	linter := testLinter(t, dir, map[string]string{
+		"sub/.vale.ini": "[*.md]\nTest.Very.tokns = [nice]\n",
+		"sub/a.md":      "This is very nice.\n",
+	})
+
+	_, err := linter.Lint([]string{dir}, "*.md")
+	if err == nil || !strings.Contains(err.Error(), "E201") || !strings.Contains(err.Error(), "tokns") {
+		t.Errorf("expected an E201 error for 'tokns', got %v", err)
+	}
+}
@@ -0,0 +1,405 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/errata-ai/vale/v2/core"
+	"github.com/gobwas/glob"
+	"github.com/jdkato/regexp"
+)
+
+// This file holds the machinery shared by the formats that we parse
+// ourselves, rather than converting them to HTML with an external program.
+//
+// The idea is to split a document into blocks (paragraphs, headings, table
+// cells, etc.) that remember exactly where they came from, which means that
+// we don't have to guess where an alert belongs.
+
+// A srcLine is a (portion of a) line of a document.
+type srcLine struct {
+	num  int    // the line's (0-based) index in the document
+	off  int    // the byte offset of text in the line
+	text string // the line's content, starting at off
+}
+
+func (ln srcLine) isBlank() bool {
+	return strings.TrimSpace(ln.text) == ""
+}
+
+// indent is the width of ln's leading whitespace.
+func (ln srcLine) indent() int {
+	n := 0
+	for _, r := range ln.text {
+		if r == ' ' {
+			n++
+		} else if r == '\t' {
+			n += 8 - n%8
+		} else {
+			break
+		}
+	}
+	return n
+}
+
+// cut removes the first i bytes of ln.
+func (ln srcLine) cut(i int) srcLine {
+	if i > len(ln.text) {
+		i = len(ln.text)
+	}
+	return srcLine{num: ln.num, off: ln.off + i, text: ln.text[i:]}
+}
+
+// dedent removes up to n columns of ln's leading whitespace.
+func (ln srcLine) dedent(n int) srcLine {
+	col, i := 0, 0
+	for i < len(ln.text) && col < n {
+		if ln.text[i] == ' ' {
+			col++
+		} else if ln.text[i] == '\t' {
+			col += 8 - col%8
+		} else {
+			break
+		}
+		i++
+	}
+	return ln.cut(i)
+}
+
+func (ln srcLine) trim() srcLine {
+	trimmed := strings.TrimLeft(ln.text, " \t")
+	ln = ln.cut(len(ln.text) - len(trimmed))
+	ln.text = strings.TrimRight(ln.text, " \t")
+	return ln
+}
+
+// toLines splits src into srcLines.
+func toLines(src string) []srcLine {
+	lines := []srcLine{}
+	for i, text := range strings.Split(src, "\n") {
+		lines = append(lines, srcLine{num: i, text: strings.TrimRight(text, " \t\r")})
+	}
+	return lines
+}
+
+// dedentAll removes the common indentation of lines.
+func dedentAll(lines []srcLine) []srcLine {
+	n := -1
+	for _, ln := range lines {
+		if !ln.isBlank() && (n < 0 || ln.indent() < n) {
+			n = ln.indent()
+		}
+	}
+
+	dedented := make([]srcLine, len(lines))
+	for i, ln := range lines {
+		dedented[i] = ln.dedent(n)
+	}
+	return dedented
+}
+
+// indented returns the end of the block of lines, starting at i, that are
+// either blank or indented by at least n columns (excluding any trailing
+// blank lines).
+func indented(lines []srcLine, i, n int) int {
+	end := i
+	for j := i; j < len(lines); j++ {
+		if lines[j].isBlank() {
+			continue
+		} else if lines[j].indent() < n {
+			break
+		}
+		end = j + 1
+	}
+	return end
+}
+
+// withTags returns a copy of tags with more appended to it.
+func withTags(tags []string, more ...string) []string {
+	return append(append([]string{}, tags...), more...)
+}
+
+// A blockKind determines how a srcBlock is linted.
+type blockKind int
+
+const (
+	textBlock    blockKind = iota // text whose scope is determined by its tags
+	attrBlock                     // an attribute's value (e.g., alt text)
+	commentBlock                  // a comment, which may control Vale
+)
+
+// A srcBlock is a unit of a document that we lint as a whole.
+type srcBlock struct {
+	kind  blockKind
+	attr  string    // the attribute's name, for an attrBlock
+	tags  []string  // the HTML tags that would enclose the block
+	lines []srcLine // the block's (consecutive) lines
+}
+
+// An inlineSpan is an inline element of a srcBlock, given by its position
+// in the block's raw text.
+type inlineSpan struct {
+	tag        string // the equivalent HTML tag -- e.g., "a" for a link
+	attr       string // the attribute's name, if the span is an attribute's value
+	start, end int
+}
+
+// inlineText is the result of stripping a srcBlock's inline markup.
+type inlineText struct {
+	text   string       // the plain text
+	masked []bool       // the bytes of the raw text that aren't part of text
+	spans  []inlineSpan // the inline elements
+}
+
+// An inlineParser converts a srcBlock's raw text into inlineText, replacing
+// any elements whose tags are in ignored (see `IgnoredScopes`).
+type inlineParser func(raw string, ignored []string) inlineText
+
+// inlineTagAliases are the tags we treat as equivalent when checking
+// `IgnoredScopes`, since they're used interchangeably by HTML converters.
+var inlineTagAliases = map[string]string{
+	"code":   "tt",
+	"strong": "b",
+	"em":     "i",
+}
+
+func isIgnoredTag(tag string, ignored []string) bool {
+	return core.StringInSlice(tag, ignored) ||
+		core.StringInSlice(inlineTagAliases[tag], ignored)
+}
+
+// A masker builds the contexts that we use to locate alerts: copies of the
+// document in which everything except for a given block has been masked,
+// so an alert can only be found where it actually occurred.
+type masker struct {
+	lines  []string // the document's lines, without their newlines
+	blank  string   // the document with every character masked
+	starts []int    // the byte offset of each line in blank
+}
+
+func newMasker(src string) masker {
+	m := masker{lines: strings.Split(src, "\n")}
+
+	var sb strings.Builder
+	for _, ln := range m.lines {
+		m.starts = append(m.starts, sb.Len())
+		sb.WriteString(strings.Repeat("@", utf8.RuneCountInString(ln)))
+		sb.WriteString("\n")
+	}
+	m.blank = sb.String()
+
+	return m
+}
+
+// context masks everything except for the bytes of lines (whose text, joined
+// by newlines, is given by keep) that are kept.
+//
+// We only include the document up to the last of the lines, which is all
+// that's needed to locate an alert.
+func (m masker) context(lines []srcLine, keep []bool) string {
+	var sb strings.Builder
+
+	first, last := lines[0].num, lines[len(lines)-1].num
+	sb.WriteString(m.blank[:m.starts[first]])
+
+	pos := 0
+	for num, k := first, 0; num <= last; num++ {
+		ln := m.lines[num]
+		if k >= len(lines) || lines[k].num != num {
+			sb.WriteString(m.blank[m.starts[num] : m.starts[num]+utf8.RuneCountInString(ln)+1])
+			continue
+		}
+
+		part := lines[k]
+		for i, r := range ln {
+			j := i - part.off
+			if j >= 0 && j < len(part.text) && keep[pos+j] {
+				sb.WriteRune(r)
+			} else {
+				sb.WriteByte('@')
+			}
+		}
+		sb.WriteString("\n")
+
+		pos += len(part.text) + 1
+		k++
+	}
+
+	return sb.String()
+}
+
+// rawText joins the text of lines by newlines.
+func rawText(lines []srcLine) string {
+	parts := make([]string, len(lines))
+	for i, ln := range lines {
+		parts[i] = ln.text
+	}
+	return strings.Join(parts, "\n")
+}
+
+// lintParsed lints the blocks of a document that we've parsed ourselves.
+//
+// src is the document's content, as given to the parser (see `blankIgnored`).
+func (l Linter) lintParsed(f *core.File, src string, blocks []srcBlock, parse inlineParser) {
+	skipped := skipTags
+	if len(l.Manager.Config.SkippedScopes) > 0 {
+		skipped = l.Manager.Config.SkippedScopes
+	}
+
+	ignored := []string{"tt", "code"}
+	if len(l.Manager.Config.IgnoredScopes) > 0 {
+		ignored = l.Manager.Config.IgnoredScopes
+	}
+
+	lines := len(f.Lines)
+	m := newMasker(src)
+
+	for _, b := range blocks {
+		if b.kind == commentBlock {
+			f.UpdateComments(strings.TrimSpace(rawText(b.lines)))
+			continue
+		} else if anyStringInSlice(b.tags, skipped) {
+			continue
+		}
+
+		trimmed := []srcLine{}
+		for _, ln := range b.lines {
+			trimmed = append(trimmed, ln.trim())
+		}
+		raw := rawText(trimmed)
+
+		in := parse(raw, ignored)
+		if strings.TrimSpace(in.text) == "" && len(in.spans) == 0 {
+			continue
+		}
+
+		keep := make([]bool, len(raw))
+		for i := range keep {
+			keep[i] = !in.masked[i]
+		}
+		ctx := m.context(trimmed, keep)
+		line := trimmed[0].num
+
+		if b.kind == attrBlock {
+			l.lintBlock(
+				f,
+				core.NewLinedBlock(ctx, in.text, "text.attr."+b.attr, line),
+				lines,
+				0,
+				true)
+			continue
+		}
+
+		for _, span := range in.spans {
+			scope, found := tagToScope[span.tag]
+			if span.attr != "" {
+				// An inline attribute, such as an inline image's alt text.
+				scope, found = "text.attr."+span.attr, true
+			} else if !found {
+				continue
+			}
+			// Only the span itself is visible in its context.
+			only := make([]bool, len(raw))
+			for i := span.start; i < span.end; i++ {
+				only[i] = true
+			}
+			l.lintBlock(
+				f,
+				core.NewLinedBlock(
+					m.context(trimmed, only), raw[span.start:span.end], scope, line),
+				lines,
+				0,
+				true)
+		}
+
+		if strings.TrimSpace(in.text) == "" {
+			// The block only consists of (masked) inline elements, such as
+			// an inline image.
+			continue
+		} else if scope, found := scopeOf(b.tags, f.RealExt); found {
+			l.lintBlock(f, core.NewLinedBlock(ctx, in.text, scope, line), lines, 0, true)
+		} else {
+			f.Summary.WriteString(in.text + " ")
+			l.lintProse(f, core.NewLinedBlock(ctx, in.text, "txt", line), lines)
+		}
+	}
+
+	l.lintSizedScopes(f)
+}
+
+// blankIgnored is the counterpart of `prep` for the formats that we parse
+// ourselves: since we need to know exactly where everything came from, it
+// masks the matches of `BlockIgnores` and `TokenIgnores` in place (rather
+// than replacing them with markup).
+//
+// Block-level matches are replaced by spaces, which means that they're
+// ignored entirely, while token-level matches are replaced by NUL bytes,
+// which a format's inlineParser treats as code.
+func (l Linter) blankIgnored(content, ext string) (string, error) {
+	s := reFrontMatter.ReplaceAllStringFunc(content, func(m string) string {
+		return blankRunes(m, ' ')
+	})
+
+	for _, step := range []struct {
+		patterns map[string][]string
+		with     rune
+	}{
+		{l.Manager.Config.BlockIgnores, ' '},
+		{l.Manager.Config.TokenIgnores, 0},
+	} {
+		for syntax, regexes := range step.patterns {
+			sec, err := glob.Compile(syntax)
+			if err != nil {
+				return s, err
+			} else if !sec.Match(ext) {
+				continue
+			}
+			for _, r := range regexes {
+				pat, err := regexp.Compile(r)
+				if err != nil {
+					return s, err
+				}
+				with := step.with
+				s = pat.ReplaceAllStringFunc(s, func(m string) string {
+					return blankRunes(m, with)
+				})
+			}
+		}
+	}
+
+	return s, nil
+}
+
+// blankRunes replaces every rune of s, other than newlines, with r.
+func blankRunes(s string, r rune) string {
+	return strings.Map(func(c rune) rune {
+		if c == '\n' {
+			return c
+		}
+		return r
+	}, s)
+}
+
+// scopeOf determines the scope of a block enclosed by the given tags: the
+// scope of the outermost tag that has one.
+func scopeOf(tags []string, ext string) (string, bool) {
+	for _, tag := range tags {
+		scope, match := tagToScope[tag]
+		if match && !core.StringInSlice(tag, inlineTags) {
+			return scope + ext, true
+		} else if heading.MatchString(tag) {
+			return fmt.Sprintf("text.heading.%s%s", tag, ext), true
+		}
+	}
+	return "", false
+}
+
+func anyStringInSlice(subs, slice []string) bool {
+	for _, s := range subs {
+		if core.StringInSlice(s, slice) {
+			return true
+		}
+	}
+	return false
+}
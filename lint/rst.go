@@ -0,0 +1,1062 @@
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/errata-ai/vale/v2/core"
+	"github.com/jdkato/regexp"
+)
+
+// Our reStructuredText parser covers the parts of the specification that
+// matter for prose: sections, paragraphs, lists, block quotes, tables,
+// directives, roles, etc.
+//
+// See https://docutils.sourceforge.io/docs/ref/rst/restructuredtext.html.
+
+var reRSTBullet = regexp.MustCompile(`^[-*+•‣⁃](?: +|$)`)
+var reRSTEnum = regexp.MustCompile(
+	`^(?:\((?:\d+|#|[a-zA-Z]|[ivxlcdmIVXLCDM]+)\)|(?:\d+|#|[a-zA-Z]|[ivxlcdmIVXLCDM]+)[.)])(?: +|$)`)
+var reRSTField = regexp.MustCompile(`^:((?:\\.|[^:\\])+):(?:\s+|$)`)
+var reRSTOptionList = regexp.MustCompile(
+	`^(?:--?|/)\w[\w-]*(?:[ =](?:<[^>]+>|[\w-]+))?(?:, (?:--?|/)\w[\w-]*(?:[ =](?:<[^>]+>|[\w-]+))?)*(?:  +|$)`)
+var reRSTFootnote = regexp.MustCompile(`^\.\.\s+\[[^\]]+\](?:\s+|$)`)
+var reRSTTarget = regexp.MustCompile(`^\.\.\s+(?:_|__:)`)
+var reRSTDirective = regexp.MustCompile(`^\.\.\s+(?:\|[^|]+\|\s+)?([\w][\w:+.-]*)::(?:\s+|$)`)
+var reRSTOption = regexp.MustCompile(`^:([^:\s][^:]*):(?:\s+|$)`)
+var reRSTGridBorder = regexp.MustCompile(`^\+-[-+]*\+$`)
+var reRSTSimpleBorder = regexp.MustCompile(`^=+(?: +=+)+$`)
+var reRSTSimpleUnderline = regexp.MustCompile(`^-+(?: +-+)*$`)
+var reRSTLineBlock = regexp.MustCompile(`^\|(?: +|$)`)
+
+// rstAdornments are the characters that can be used to adorn a section
+// title.
+const rstAdornments = "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+
+// rstDirectives are the directives whose content we lint, and how we treat
+// them; we skip all others (e.g., `code-block`, `raw`, `math`, and any that
+// we don't know about).
+var rstDirectives = map[string]string{
+	// Admonitions: their content may start on the first line.
+	"attention": "content",
+	"caution":   "content",
+	"danger":    "content",
+	"error":     "content",
+	"hint":      "content",
+	"important": "content",
+	"note":      "content",
+	"tip":       "content",
+	"warning":   "content",
+	"seealso":   "content",
+	"todo":      "content",
+
+	// Directives whose argument is a title.
+	"admonition": "titled",
+	"topic":      "titled",
+	"sidebar":    "titled",
+	"rubric":     "titled",
+	"centered":   "titled",
+	"table":      "titled",
+
+	// Directives whose argument is a version, optionally followed by text.
+	"versionadded":   "version",
+	"versionchanged": "version",
+	"versionremoved": "version",
+	"deprecated":     "version",
+
+	// Directives whose argument (if any) isn't prose.
+	"container": "body",
+	"compound":  "body",
+	"only":      "body",
+	"hlist":     "body",
+	"glossary":  "body",
+	"class":     "body",
+
+	"epigraph":   "quote",
+	"highlights": "quote",
+	"pull-quote": "quote",
+
+	"image":      "image",
+	"figure":     "figure",
+	"list-table": "list-table",
+}
+
+// rstRoles are the HTML tags equivalent to the roles we know about;
+// unknown roles are treated as code.
+var rstRoles = map[string]string{
+	"emphasis":        "em",
+	"strong":          "strong",
+	"literal":         "code",
+	"code":            "code",
+	"math":            "code",
+	"title-reference": "cite",
+	"title":           "cite",
+	"t":               "cite",
+	"subscript":       "sub",
+	"sub":             "sub",
+	"superscript":     "sup",
+	"sup":             "sup",
+	"abbreviation":    "abbr",
+	"ab":              "abbr",
+	"abbr":            "abbr",
+	"acronym":         "acronym",
+	"ac":              "acronym",
+	"dfn":             "dfn",
+	"guilabel":        "span",
+	"menuselection":   "span",
+	"term":            "a",
+	"ref":             "a",
+	"doc":             "a",
+	"numref":          "a",
+	"any":             "a",
+	"download":        "a",
+	"pep-reference":   "a",
+	"rfc-reference":   "a",
+}
+
+// rstLabelRoles are the reference roles whose content, unless it has an
+// explicit title, is a label rather than prose.
+var rstLabelRoles = []string{"ref", "doc", "numref", "any", "download"}
+
+// An rstParser splits a reStructuredText document into srcBlocks.
+type rstParser struct {
+	styles []string // section title styles, in the order we first see them
+	blocks []srcBlock
+	err    error // the first malformed construct, if any
+}
+
+// parseRST splits src into the blocks that we lint.
+//
+// Like Docutils, we don't give up on a document with malformed markup (such
+// as a table whose rows don't line up): we return the blocks that we could
+// parse along with an error describing the first problem.
+func parseRST(src string) ([]srcBlock, error) {
+	p := rstParser{}
+	p.body(toLines(src), nil)
+	return p.blocks, p.err
+}
+
+// malformed records that the construct at ln is invalid.
+func (p *rstParser) malformed(ln srcLine, msg string) {
+	if p.err == nil {
+		p.err = fmt.Errorf("line %d: %s", ln.num+1, msg)
+	}
+}
+
+func (p *rstParser) add(kind blockKind, tags []string, lines []srcLine) {
+	for _, ln := range lines {
+		if !ln.isBlank() {
+			p.blocks = append(p.blocks, srcBlock{kind: kind, tags: tags, lines: lines})
+			return
+		}
+	}
+}
+
+// body parses a sequence of body elements.
+func (p *rstParser) body(lines []srcLine, tags []string) {
+	for i := 0; i < len(lines); {
+		i = p.element(lines, i, tags)
+	}
+}
+
+// element parses the body element starting at lines[i], returning the index
+// of the line after it.
+func (p *rstParser) element(lines []srcLine, i int, tags []string) int {
+	text := lines[i].text
+
+	switch {
+	case lines[i].isBlank():
+		return i + 1
+	case lines[i].indent() > 0:
+		end := indented(lines, i, 1)
+		p.body(dedentAll(lines[i:end]), withTags(tags, "blockquote"))
+		return end
+	case text == ".." || strings.HasPrefix(text, ".. ") || strings.HasPrefix(text, "..\t"):
+		return p.explicit(lines, i, tags)
+	case text == "__" || strings.HasPrefix(text, "__ "):
+		// An anonymous hyperlink target.
+		return indented(lines, i+1, 1)
+	case reRSTGridBorder.MatchString(text):
+		if end := p.gridTable(lines, i, tags); end > i {
+			return end
+		}
+	case reRSTSimpleBorder.MatchString(text):
+		if end := p.simpleTable(lines, i, tags); end > i {
+			return end
+		}
+	case strings.HasPrefix(text, ">>> ") || text == ">>>":
+		// A doctest block, which ends at the first blank line.
+		for i < len(lines) && !lines[i].isBlank() {
+			i++
+		}
+		return i
+	}
+
+	if end := p.section(lines, i, tags); end > i {
+		return end
+	} else if isRSTAdornment(text) && len(text) >= 4 {
+		// A transition.
+		return i + 1
+	}
+
+	if m := reRSTBullet.FindStringIndex(text); m != nil {
+		return p.item(lines, i, m[1], withTags(tags, "ul", "li"))
+	} else if m := reRSTEnum.FindStringIndex(text); m != nil && p.isEnumerated(lines, i) {
+		return p.item(lines, i, m[1], withTags(tags, "ol", "li"))
+	} else if m := reRSTField.FindStringIndex(text); m != nil {
+		return p.item(lines, i, m[1], withTags(tags, "dl", "dd"))
+	} else if m := reRSTOptionList.FindStringIndex(text); m != nil && p.isOption(lines, i, m[1]) {
+		return p.item(lines, i, m[1], withTags(tags, "dl", "dd"))
+	} else if reRSTLineBlock.MatchString(text) {
+		return p.lineBlock(lines, i, tags)
+	} else if i+1 < len(lines) && !lines[i+1].isBlank() && lines[i+1].indent() > 0 {
+		return p.definition(lines, i, tags)
+	}
+
+	return p.paragraph(lines, i, tags)
+}
+
+// isEnumerated determines if lines[i], which starts with an enumerator, is
+// actually a list item (rather than, e.g., a paragraph starting with an
+// initial).
+func (p *rstParser) isEnumerated(lines []srcLine, i int) bool {
+	if i+1 == len(lines) {
+		return true
+	}
+	next := lines[i+1]
+	return next.isBlank() || next.indent() > 0 || reRSTEnum.MatchString(next.text)
+}
+
+// isOption determines if lines[i] is an option list item: an option needs a
+// description, either on the same line or indented beneath it.
+func (p *rstParser) isOption(lines []srcLine, i, end int) bool {
+	if strings.TrimSpace(lines[i].text[end:]) != "" {
+		return true
+	}
+	return i+1 < len(lines) && !lines[i+1].isBlank() && lines[i+1].indent() > 0
+}
+
+// item parses a list item (or field, option, etc.) whose marker ends at
+// byte `end` of lines[i].
+func (p *rstParser) item(lines []srcLine, i, end int, tags []string) int {
+	body, next := itemBody(lines, i, end)
+	p.body(body, tags)
+	return next
+}
+
+// itemBody returns the content of the item whose marker ends at byte `end`
+// of lines[i], along with the index of the line after it.
+func itemBody(lines []srcLine, i, end int) ([]srcLine, int) {
+	next := indented(lines, i+1, 1)
+	body := append([]srcLine{lines[i].cut(end)}, dedentAll(lines[i+1:next])...)
+	return body, next
+}
+
+func (p *rstParser) lineBlock(lines []srcLine, i int, tags []string) int {
+	block := []srcLine{}
+	for ; i < len(lines); i++ {
+		if m := reRSTLineBlock.FindStringIndex(lines[i].text); m != nil {
+			block = append(block, lines[i].cut(m[1]))
+		} else if !lines[i].isBlank() && lines[i].indent() > 0 {
+			block = append(block, lines[i])
+		} else {
+			break
+		}
+	}
+	p.add(textBlock, withTags(tags, "div"), block)
+	return i
+}
+
+func (p *rstParser) definition(lines []srcLine, i int, tags []string) int {
+	term := lines[i]
+	if idx := strings.Index(term.text, " : "); idx > 0 {
+		// Drop the term's classifiers.
+		term.text = term.text[:idx]
+	}
+	p.add(textBlock, withTags(tags, "dl", "dt"), []srcLine{term})
+
+	end := indented(lines, i+1, 1)
+	p.body(dedentAll(lines[i+1:end]), withTags(tags, "dl", "dd"))
+
+	return end
+}
+
+func (p *rstParser) paragraph(lines []srcLine, i int, tags []string) int {
+	end := i + 1
+	for end < len(lines) && !lines[end].isBlank() && lines[end].indent() == 0 {
+		end++
+	}
+
+	para := append([]srcLine{}, lines[i:end]...)
+	last := &para[len(para)-1]
+
+	literal := strings.HasSuffix(last.text, "::")
+	if literal {
+		// The paragraph introduces a literal block: "Paragraph::" becomes
+		// "Paragraph:", while "Paragraph ::" and "::" are removed entirely.
+		trimmed := strings.TrimRight(last.text[:len(last.text)-2], " \t")
+		if trimmed == "" {
+			para = para[:len(para)-1]
+		} else if len(trimmed) < len(last.text)-2 {
+			last.text = trimmed
+		} else {
+			last.text = last.text[:len(last.text)-1]
+		}
+	}
+	p.add(textBlock, withTags(tags, "p"), para)
+
+	if literal {
+		return p.literal(lines, end, tags)
+	}
+	return end
+}
+
+// literal parses the (indented or quoted) literal block that starts after
+// lines[i].
+func (p *rstParser) literal(lines []srcLine, i int, tags []string) int {
+	for i < len(lines) && lines[i].isBlank() {
+		i++
+	}
+	if i == len(lines) {
+		return i
+	}
+
+	end := i
+	if lines[i].indent() > 0 {
+		end = indented(lines, i, 1)
+	} else if r, _ := utf8.DecodeRuneInString(lines[i].text); strings.ContainsRune(rstAdornments, r) {
+		for end < len(lines) && strings.HasPrefix(lines[end].text, string(r)) {
+			end++
+		}
+	}
+	p.add(textBlock, withTags(tags, "pre"), lines[i:end])
+
+	return end
+}
+
+// section parses the section title at lines[i] (if there is one).
+func (p *rstParser) section(lines []srcLine, i int, tags []string) int {
+	text := lines[i].text
+	if isRSTAdornment(text) && i+2 < len(lines) && !lines[i+1].isBlank() {
+		// An overlined title.
+		under := lines[i+2].text
+		if isRSTAdornment(under) && under[0] == text[0] {
+			p.heading(lines[i+1], "o"+text[:1], tags)
+			return i + 3
+		} else if len(text) >= 4 && !isRSTAdornment(lines[i+1].text) {
+			p.malformed(lines[i], "malformed section title: the overline has no matching underline")
+		}
+	}
+
+	if i+1 < len(lines) && !isRSTAdornment(text) {
+		under := lines[i+1].text
+		width := utf8.RuneCountInString(strings.TrimSpace(text))
+		if isRSTAdornment(under) && (len(under) >= 4 || len(under) >= width) {
+			p.heading(lines[i], under[:1], tags)
+			return i + 2
+		}
+	}
+
+	return i
+}
+
+func (p *rstParser) heading(title srcLine, style string, tags []string) {
+	level := 0
+	for idx, s := range p.styles {
+		if s == style {
+			level = idx + 1
+		}
+	}
+	if level == 0 {
+		p.styles = append(p.styles, style)
+		level = len(p.styles)
+	}
+	if level > 6 {
+		level = 6
+	}
+	p.add(textBlock, withTags(tags, fmt.Sprintf("h%d", level)), []srcLine{title})
+}
+
+// isRSTAdornment determines if text could be a section title's adornment.
+func isRSTAdornment(text string) bool {
+	if len(text) < 2 || !strings.ContainsRune(rstAdornments, rune(text[0])) {
+		return false
+	}
+	return strings.Count(text, text[:1]) == len(text)
+}
+
+// explicit parses an explicit markup block -- i.e., a footnote, citation,
+// hyperlink target, directive, substitution definition, or comment.
+func (p *rstParser) explicit(lines []srcLine, i int, tags []string) int {
+	text := lines[i].text
+	if strings.TrimSpace(text) == ".." && (i+1 == len(lines) || lines[i+1].isBlank()) {
+		// An empty comment, which may be used to end a preceding construct.
+		return i + 1
+	}
+	end := indented(lines, i+1, 1)
+
+	if m := reRSTFootnote.FindStringIndex(text); m != nil {
+		body, _ := itemBody(lines, i, m[1])
+		p.body(body, withTags(tags, "div"))
+	} else if reRSTTarget.MatchString(text) {
+		// A hyperlink target, which doesn't have any prose.
+	} else if m := reRSTDirective.FindStringSubmatchIndex(text); m != nil {
+		p.directive(text[m[2]:m[3]], lines[i:end], m[1], tags)
+	} else {
+		comment := append([]srcLine{lines[i].cut(2)}, lines[i+1:end]...)
+		p.blocks = append(p.blocks, srcBlock{kind: commentBlock, lines: comment})
+	}
+
+	return end
+}
+
+// directive parses a directive's block, whose arguments start at byte `start`
+// of its first line.
+func (p *rstParser) directive(name string, lines []srcLine, start int, tags []string) {
+	kind, found := rstDirectives[strings.ToLower(name)]
+	if !found {
+		return
+	}
+
+	rest := dedentAll(lines[1:])
+
+	args := []srcLine{}
+	if first := lines[0].cut(start); !first.isBlank() {
+		args = append(args, first)
+	}
+	k := 0
+	for k < len(rest) && !rest[k].isBlank() && !reRSTOption.MatchString(rest[k].text) {
+		args = append(args, rest[k])
+		k++
+	}
+
+	options := map[string]srcLine{}
+	for k < len(rest) && !rest[k].isBlank() {
+		if m := reRSTOption.FindStringSubmatchIndex(rest[k].text); m != nil {
+			options[rest[k].text[m[2]:m[3]]] = rest[k].cut(m[1])
+		}
+		k++
+	}
+	content := rest[k:]
+
+	switch kind {
+	case "content":
+		p.body(append(args, content...), withTags(tags, "div"))
+	case "titled":
+		p.add(textBlock, withTags(tags, "p"), args)
+		p.body(content, withTags(tags, "div"))
+	case "version":
+		if len(args) > 0 {
+			if idx := strings.IndexAny(args[0].text, " \t"); idx > 0 {
+				args[0] = args[0].cut(idx)
+			} else {
+				args = args[1:]
+			}
+		}
+		p.body(append(args, content...), withTags(tags, "div"))
+	case "body":
+		p.body(content, withTags(tags, "div"))
+	case "quote":
+		p.body(content, withTags(tags, "blockquote"))
+	case "image", "figure":
+		if alt, found := options["alt"]; found && !alt.isBlank() {
+			p.blocks = append(p.blocks, srcBlock{
+				kind: attrBlock, attr: "alt", tags: tags, lines: []srcLine{alt}})
+		}
+		if kind == "figure" {
+			p.body(content, withTags(tags, "figure"))
+		}
+	case "list-table":
+		p.add(textBlock, withTags(tags, "p"), args)
+
+		headers := 0
+		if opt, found := options["header-rows"]; found {
+			headers, _ = strconv.Atoi(strings.TrimSpace(opt.text))
+		}
+		for r, row := range rstItems(dedentAll(content)) {
+			tag := "td"
+			if r < headers {
+				tag = "th"
+			}
+			for _, cell := range rstItems(dedentAll(row)) {
+				p.body(cell, withTags(tags, "table", tag))
+			}
+		}
+	}
+}
+
+// rstItems returns the content of each item of a bullet list.
+func rstItems(lines []srcLine) [][]srcLine {
+	items := [][]srcLine{}
+	for i := 0; i < len(lines); {
+		if m := reRSTBullet.FindStringIndex(lines[i].text); m != nil && lines[i].indent() == 0 {
+			var body []srcLine
+			body, i = itemBody(lines, i, m[1])
+			items = append(items, body)
+		} else {
+			i++
+		}
+	}
+	return items
+}
+
+// gridTable parses the grid table starting at lines[i].
+//
+// This follows Docutils' algorithm: we find each cell by tracing its borders
+// from its top-left corner, which allows for cells that span rows and
+// columns.
+func (p *rstParser) gridTable(lines []srcLine, i int, tags []string) int {
+	end := i
+	for end < len(lines) && (strings.HasPrefix(lines[end].text, "+") || strings.HasPrefix(lines[end].text, "|")) {
+		end++
+	}
+
+	g := gridScanner{header: -1}
+	width := utf8.RuneCountInString(lines[i].text)
+	for r, ln := range lines[i:end] {
+		row := []rune(ln.text)
+		if len(row) > width {
+			p.malformed(ln, "malformed table: the line is wider than the table's border")
+			return end
+		} else if strings.HasPrefix(ln.text, "+=") {
+			g.header = r
+			row = []rune(strings.Replace(ln.text, "=", "-", -1))
+		}
+		for len(row) < width {
+			row = append(row, ' ')
+		}
+		g.grid = append(g.grid, row)
+	}
+
+	cells := g.scan()
+	if r := g.uncovered(cells); r >= 0 {
+		// Some of the table's content isn't in any cell, which means that a
+		// row doesn't line up with the table's borders.
+		p.malformed(lines[i+r], "malformed table: the row doesn't match the table's borders")
+	} else if len(cells) == 0 {
+		return i
+	}
+
+	for _, c := range cells {
+		cell := []srcLine{}
+		for r := c.top + 1; r < c.bottom; r++ {
+			ln := lines[i+r]
+			row := []rune(ln.text)
+
+			left, right := c.left+1, c.right
+			if right > len(row) {
+				right = len(row)
+			}
+			if left >= right {
+				cell = append(cell, srcLine{num: ln.num, off: ln.off + len(ln.text)})
+				continue
+			}
+
+			off := len(string(row[:left]))
+			cell = append(cell, srcLine{
+				num: ln.num, off: ln.off + off, text: string(row[left:right])})
+		}
+
+		tag := "td"
+		if g.header > 0 && c.bottom <= g.header {
+			tag = "th"
+		}
+		p.body(dedentAll(cell), withTags(tags, "table", tag))
+	}
+
+	return end
+}
+
+type gridCell struct {
+	top, left, bottom, right int
+}
+
+type gridScanner struct {
+	grid   [][]rune
+	header int   // the row of the header separator, if any
+	done   []int // the last row of the cells found in each column
+}
+
+func (g *gridScanner) scan() []gridCell {
+	cells := []gridCell{}
+
+	bottom, right := len(g.grid)-1, len(g.grid[0])-1
+	g.done = make([]int, right+1)
+	for i := range g.done {
+		g.done[i] = -1
+	}
+
+	corners := [][2]int{{0, 0}}
+	for len(corners) > 0 {
+		top, left := corners[0][0], corners[0][1]
+		corners = corners[1:]
+
+		if top == bottom || left == right || top <= g.done[left] {
+			continue
+		}
+
+		c, found := g.scanRight(top, left)
+		if !found {
+			continue
+		}
+		for col := c.left; col < c.right; col++ {
+			g.done[col] = c.bottom - 1
+		}
+		cells = append(cells, c)
+
+		corners = append(corners, [2]int{c.top, c.right}, [2]int{c.bottom, c.left})
+		sort.Slice(corners, func(a, b int) bool {
+			if corners[a][0] != corners[b][0] {
+				return corners[a][0] < corners[b][0]
+			}
+			return corners[a][1] < corners[b][1]
+		})
+	}
+
+	return cells
+}
+
+// uncovered returns the first row of the grid with text that's outside of
+// every cell, or -1 if there isn't one.
+func (g *gridScanner) uncovered(cells []gridCell) int {
+	for r, row := range g.grid {
+		for col, c := range row {
+			if strings.ContainsRune(" +-|", c) {
+				continue
+			}
+			found := false
+			for _, cell := range cells {
+				if r > cell.top && r < cell.bottom && col > cell.left && col < cell.right {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return r
+			}
+		}
+	}
+	return -1
+}
+
+func (g *gridScanner) scanRight(top, left int) (gridCell, bool) {
+	line := g.grid[top]
+	for col := left + 1; col < len(line); col++ {
+		if line[col] == '+' {
+			if bottom, found := g.scanDown(top, left, col); found {
+				return gridCell{top: top, left: left, bottom: bottom, right: col}, true
+			}
+		} else if line[col] != '-' {
+			return gridCell{}, false
+		}
+	}
+	return gridCell{}, false
+}
+
+func (g *gridScanner) scanDown(top, left, right int) (int, bool) {
+	for row := top + 1; row < len(g.grid); row++ {
+		if g.grid[row][right] == '+' {
+			if g.scanLeft(top, left, row, right) {
+				return row, true
+			}
+		} else if g.grid[row][right] != '|' {
+			return 0, false
+		}
+	}
+	return 0, false
+}
+
+func (g *gridScanner) scanLeft(top, left, bottom, right int) bool {
+	line := g.grid[bottom]
+	for col := right - 1; col > left; col-- {
+		if line[col] != '+' && line[col] != '-' {
+			return false
+		}
+	}
+	if line[left] != '+' {
+		return false
+	}
+	for row := bottom - 1; row > top; row-- {
+		if g.grid[row][left] != '+' && g.grid[row][left] != '|' {
+			return false
+		}
+	}
+	return true
+}
+
+// simpleTable parses the simple table starting at lines[i].
+func (p *rstParser) simpleTable(lines []srcLine, i int, tags []string) int {
+	// The columns are given by the top border.
+	cols := []int{}
+	for idx, r := range []rune(lines[i].text) {
+		if r == '=' && (idx == 0 || lines[i].text[idx-1] == ' ') {
+			cols = append(cols, idx)
+		}
+	}
+
+	borders := []int{i}
+	end := -1
+	for j := i + 1; j < len(lines) && end < 0; j++ {
+		if reRSTSimpleBorder.MatchString(lines[j].text) {
+			borders = append(borders, j)
+			if j+1 == len(lines) || lines[j+1].isBlank() {
+				end = j
+			}
+		}
+	}
+	if end < 0 {
+		p.malformed(lines[i], "malformed table: there's no bottom border")
+		end = i
+		for end < len(lines) && !lines[end].isBlank() {
+			end++
+		}
+		return end
+	}
+
+	header := -1
+	if len(borders) > 2 {
+		header = borders[1]
+	}
+
+	// Each row starts with a line that has text in its first column; any
+	// other lines continue the row above them.
+	rows := [][]srcLine{}
+	for j := i + 1; j < end; j++ {
+		ln := lines[j]
+		if ln.isBlank() || reRSTSimpleBorder.MatchString(ln.text) || reRSTSimpleUnderline.MatchString(ln.text) {
+			continue
+		}
+		first := []rune(ln.text)
+		if len(rows) == 0 || (len(cols) > 1 && strings.TrimSpace(string(runeSlice(first, 0, cols[1]))) != "") {
+			rows = append(rows, []srcLine{})
+		}
+		rows[len(rows)-1] = append(rows[len(rows)-1], ln)
+	}
+
+	for _, row := range rows {
+		tag := "td"
+		if header > 0 && row[0].num < lines[header].num {
+			tag = "th"
+		}
+		for c := range cols {
+			cell := []srcLine{}
+			for _, ln := range row {
+				runes := []rune(ln.text)
+				stop := len(runes)
+				if c+1 < len(cols) {
+					stop = cols[c+1]
+				}
+				part := runeSlice(runes, cols[c], stop)
+				off := len(string(runeSlice(runes, 0, cols[c])))
+				cell = append(cell, srcLine{num: ln.num, off: ln.off + off, text: string(part)})
+			}
+			p.body(dedentAll(cell), withTags(tags, "table", tag))
+		}
+	}
+
+	return end + 1
+}
+
+// runeSlice returns runes[i:j], clamped to its bounds.
+func runeSlice(runes []rune, i, j int) []rune {
+	if j > len(runes) {
+		j = len(runes)
+	}
+	if i > j {
+		i = j
+	}
+	return runes[i:j]
+}
+
+// rstInline strips the inline markup from raw reStructuredText.
+type rstInline struct {
+	raw     string
+	ignored []string
+
+	sb     strings.Builder
+	masked []bool
+	spans  []inlineSpan
+}
+
+// parseRSTInline is the inlineParser for reStructuredText.
+func parseRSTInline(raw string, ignored []string) inlineText {
+	p := rstInline{raw: raw, ignored: ignored, masked: make([]bool, len(raw))}
+	p.parse()
+	return inlineText{text: p.sb.String(), masked: p.masked, spans: p.spans}
+}
+
+func (p *rstInline) mask(start, end int) {
+	for i := start; i < end; i++ {
+		p.masked[i] = true
+	}
+}
+
+// emit writes the element raw[start:end] with the given tag.
+func (p *rstInline) emit(tag string, start, end int) {
+	p.spans = append(p.spans, inlineSpan{tag: tag, start: start, end: end})
+	if isIgnoredTag(tag, p.ignored) {
+		p.mask(start, end)
+		stars := strings.Repeat("*", utf8.RuneCountInString(p.raw[start:end]))
+		p.sb.WriteString(codify(".rst", stars))
+	} else {
+		p.sb.WriteString(p.raw[start:end])
+	}
+}
+
+func (p *rstInline) parse() {
+	s := p.raw
+	for i := 0; i < len(s); {
+		if next := p.markup(i); next > i {
+			i = next
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(s[i:])
+		p.sb.WriteString(s[i : i+size])
+		i += size
+	}
+}
+
+// markup parses the inline markup (if any) starting at raw[i], returning
+// the index after it.
+func (p *rstInline) markup(i int) int {
+	s := p.raw
+
+	switch c := s[i]; {
+	case c == '\\' && i+1 < len(s):
+		// An escaped character: escaped whitespace is removed entirely.
+		r, size := utf8.DecodeRuneInString(s[i+1:])
+		p.mask(i, i+1)
+		if unicode.IsSpace(r) {
+			p.mask(i+1, i+1+size)
+		} else {
+			p.sb.WriteString(s[i+1 : i+1+size])
+		}
+		return i + 1 + size
+	case c == 0:
+		// A match of `TokenIgnores`.
+		end := i
+		for end < len(s) && s[end] == 0 {
+			end++
+		}
+		p.mask(i, end)
+		p.sb.WriteString(codify(".rst", strings.Repeat("*", end-i)))
+		return end
+	case strings.HasPrefix(s[i:], "``") && rstStart(s, i, 2):
+		if end := rstEnd(s, i+2, "``", false); end > 0 {
+			p.mask(i, i+2)
+			p.mask(end, end+2)
+			p.emit("code", i+2, end)
+			return end + 2
+		}
+	case c == ':' && rstStart(s, i, 1):
+		if m := reRSTRolePrefix.FindStringSubmatchIndex(s[i:]); m != nil {
+			open := i + m[1]
+			if end := rstEnd(s, open, "`", true); end > 0 {
+				p.mask(i, open)
+				p.mask(end, end+1)
+				p.role(s[i+m[2]:i+m[3]], open, end)
+				return end + 1
+			}
+		}
+	case c == '`' && rstStart(s, i, 1):
+		if end := rstEnd(s, i+1, "`", true); end > 0 {
+			return p.interpreted(i, end)
+		}
+	case strings.HasPrefix(s[i:], "_`") && rstStart(s, i, 2):
+		// An inline internal target.
+		if end := rstEnd(s, i+2, "`", true); end > 0 {
+			p.mask(i, i+2)
+			p.mask(end, end+1)
+			p.emit("span", i+2, end)
+			return end + 1
+		}
+	case strings.HasPrefix(s[i:], "**") && rstStart(s, i, 2):
+		if end := rstEnd(s, i+2, "**", true); end > 0 {
+			p.mask(i, i+2)
+			p.mask(end, end+2)
+			p.emit("strong", i+2, end)
+			return end + 2
+		}
+	case c == '*' && rstStart(s, i, 1):
+		if end := rstEnd(s, i+1, "*", true); end > 0 {
+			p.mask(i, i+1)
+			p.mask(end, end+1)
+			p.emit("em", i+1, end)
+			return end + 1
+		}
+	case c == '|' && rstStart(s, i, 1):
+		// A substitution reference, which we drop.
+		if end := rstEnd(s, i+1, "|", true); end > 0 {
+			end++
+			for n := 0; n < 2 && end < len(s) && s[end] == '_'; n++ {
+				end++
+			}
+			p.mask(i, end)
+			return end
+		}
+	case c == '[' && rstStart(s, i, 0):
+		// A footnote or citation reference, which we drop.
+		if m := reRSTFootnoteRef.FindStringIndex(s[i:]); m != nil && rstFollows(s, i+m[1]) {
+			p.mask(i, i+m[1])
+			return i + m[1]
+		}
+	case isRSTWordRune(s, i) && (i == 0 || !isRSTWordRune(s, i-1)):
+		// A simple (one-word) reference -- e.g., "Python_".
+		if m := reRSTSimpleRef.FindStringSubmatchIndex(s[i:]); m != nil && rstFollows(s, i+m[1]) {
+			p.emit("a", i, i+m[3])
+			p.mask(i+m[3], i+m[1])
+			return i + m[1]
+		}
+	}
+
+	return i
+}
+
+var reRSTRolePrefix = regexp.MustCompile("^:([\\w][\\w:+.-]*):`")
+var reRSTRoleSuffix = regexp.MustCompile(`^:([\w][\w:+.-]*):`)
+var reRSTFootnoteRef = regexp.MustCompile(`^\[(?:\d+|#[\w-]*|\*|[\w][\w.-]*)\]_`)
+var reRSTSimpleRef = regexp.MustCompile(`^([^\W_]+(?:[-.+:_][^\W_]+)*)__?`)
+var reRSTExplicitTarget = regexp.MustCompile(`(?s)^(.*?)\s*<([^<>]+)>$`)
+
+// interpreted parses the interpreted text `raw[start+1:end]`, which could be
+// a hyperlink reference, have a role, or be a title reference.
+func (p *rstInline) interpreted(start, end int) int {
+	s := p.raw
+
+	p.mask(start, start+1)
+	p.mask(end, end+1)
+
+	after := end + 1
+	if strings.HasPrefix(s[after:], "__") && rstFollows(s, after+2) {
+		p.mask(after, after+2)
+		p.reference(start+1, end)
+		return after + 2
+	} else if strings.HasPrefix(s[after:], "_") && rstFollows(s, after+1) {
+		p.mask(after, after+1)
+		p.reference(start+1, end)
+		return after + 1
+	} else if m := reRSTRoleSuffix.FindStringSubmatchIndex(s[after:]); m != nil && rstFollows(s, after+m[1]) {
+		p.mask(after, after+m[1])
+		p.role(s[after+m[2]:after+m[3]], start+1, end)
+		return after + m[1]
+	}
+
+	p.emit("cite", start+1, end)
+	return after
+}
+
+// reference parses a hyperlink reference, whose text may be followed by an
+// embedded URI or alias -- e.g., `Python <https://www.python.org>`_.
+func (p *rstInline) reference(start, end int) {
+	if m := reRSTExplicitTarget.FindStringSubmatchIndex(p.raw[start:end]); m != nil {
+		if m[3] > m[2] {
+			p.mask(start+m[3], end)
+			p.emit("a", start, start+m[3])
+			return
+		}
+		p.mask(start, start+m[4])
+		p.mask(start+m[5], end)
+		p.emit("a", start+m[4], start+m[5])
+		return
+	}
+	p.emit("a", start, end)
+}
+
+// role parses the interpreted text raw[start:end], which has the given role.
+func (p *rstInline) role(name string, start, end int) {
+	tag, found := rstRoles[strings.ToLower(name)]
+	if !found {
+		tag = "code"
+	}
+
+	if tag == "a" {
+		m := reRSTExplicitTarget.FindStringSubmatchIndex(p.raw[start:end])
+		if m != nil && m[3] > m[2] {
+			p.mask(start+m[3], end)
+			p.emit("a", start, start+m[3])
+			return
+		} else if core.StringInSlice(name, rstLabelRoles) {
+			tag = "code"
+		}
+	}
+
+	p.emit(tag, start, end)
+}
+
+// rstStart determines if raw[i:i+n] is a valid inline markup start-string:
+// it has to start a word and be followed by something other than whitespace.
+func rstStart(s string, i, n int) bool {
+	if i > 0 {
+		r, _ := utf8.DecodeLastRuneInString(s[:i])
+		if !unicode.IsSpace(r) && !strings.ContainsRune(`-:/'"<([{`, r) &&
+			!unicode.In(r, unicode.Ps, unicode.Pi, unicode.Pd) {
+			return false
+		}
+	}
+	if i+n >= len(s) {
+		return false
+	}
+	r, _ := utf8.DecodeRuneInString(s[i+n:])
+	return !unicode.IsSpace(r)
+}
+
+// rstEnd finds the end-string delim of the inline markup whose content
+// starts at raw[from], returning -1 if there isn't one.
+func rstEnd(s string, from int, delim string, escapes bool) int {
+	for i := from + 1; i+len(delim) <= len(s); i++ {
+		if !strings.HasPrefix(s[i:], delim) {
+			continue
+		} else if escapes && s[i-1] == '\\' {
+			continue
+		}
+		r, _ := utf8.DecodeLastRuneInString(s[:i])
+		if unicode.IsSpace(r) {
+			continue
+		} else if delim == "*" && strings.HasPrefix(s[i+1:], "*") {
+			continue
+		} else if delim == "`" && (strings.HasPrefix(s[i+1:], "_") || strings.HasPrefix(s[i+1:], ":")) {
+			// A reference or role suffix, which the caller handles.
+			return i
+		} else if rstFollows(s, i+len(delim)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// rstFollows determines if raw[i] can follow an inline markup end-string.
+func rstFollows(s string, i int) bool {
+	if i >= len(s) {
+		return true
+	}
+	r, _ := utf8.DecodeRuneInString(s[i:])
+	return unicode.IsSpace(r) || strings.ContainsRune(`-.,:;!?\/'")]}>`, r) ||
+		unicode.In(r, unicode.Pe, unicode.Pf, unicode.Pd, unicode.Po)
+}
+
+func isRSTWordRune(s string, i int) bool {
+	r, _ := utf8.DecodeRuneInString(s[i:])
+	if i > 0 && !utf8.RuneStart(s[i]) {
+		return true
+	}
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// lintRSTNative lints a reStructuredText file with our own parser.
+func (l Linter) lintRSTNative(f *core.File) error {
+	src, err := l.blankIgnored(f.Content, ".rst")
+	if err != nil {
+		return core.NewE100(f.Path, err)
+	}
+	blocks, err := parseRST(src)
+	l.lintParsed(f, src, blocks, parseRSTInline)
+	if err != nil {
+		// We still lint the rest of the document (see `parseRST`).
+		return core.NewE100(f.Path, err)
+	}
+	return nil
+}
@@ -0,0 +1,413 @@
+package lint
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// describeBlock summarizes a block as its tags (or kind) followed by the
+// position (1-based line and column) and text of each of its lines.
+func describeBlock(b srcBlock) string {
+	kind := strings.Join(b.tags, "/")
+	switch b.kind {
+	case attrBlock:
+		kind = "attr:" + b.attr
+	case commentBlock:
+		kind = "comment"
+	}
+
+	parts := []string{}
+	for _, ln := range b.lines {
+		parts = append(parts, fmt.Sprintf("%d:%d %s", ln.num+1, ln.off+1, ln.text))
+	}
+	return kind + " " + strings.Join(parts, " | ")
+}
+
+func describeBlocks(blocks []srcBlock) []string {
+	described := []string{}
+	for _, b := range blocks {
+		described = append(described, describeBlock(b))
+	}
+	return described
+}
+
+func TestParseRST(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		src    string
+		blocks []string
+	}{
+		{
+			name: "sections",
+			src:  "Title\n=====\n\nSub\n---\n\nText.\n\nOther\n=====\n",
+			blocks: []string{
+				"h1 1:1 Title",
+				"h2 4:1 Sub",
+				"p 7:1 Text.",
+				"h1 9:1 Other",
+			},
+		},
+		{
+			name:   "overlined section",
+			src:    "=====\nTitle\n=====\n\nText.\n",
+			blocks: []string{"h1 2:1 Title", "p 5:1 Text."},
+		},
+		{
+			// Docutils treats a title with a short underline as text.
+			name:   "short underline",
+			src:    "Title\n===\n",
+			blocks: []string{"p 1:1 Title | 2:1 ==="},
+		},
+		{
+			name: "paragraphs and block quotes",
+			src:  "A paragraph\nthat wraps.\n\n  A quote.\n",
+			blocks: []string{
+				"p 1:1 A paragraph | 2:1 that wraps.",
+				"blockquote/p 4:3 A quote.",
+			},
+		},
+		{
+			name:   "transitions",
+			src:    "Before.\n\n----\n\nAfter.\n",
+			blocks: []string{"p 1:1 Before.", "p 5:1 After."},
+		},
+		{
+			name: "lists",
+			src:  "- One\n- Two\n  continued\n\n  Second para.\n\n1. First\n2. Second\n\n#) Auto\n",
+			blocks: []string{
+				"ul/li/p 1:3 One",
+				"ul/li/p 2:3 Two | 3:3 continued",
+				"ul/li/p 5:3 Second para.",
+				"ol/li/p 7:4 First",
+				"ol/li/p 8:4 Second",
+				"ol/li/p 10:4 Auto",
+			},
+		},
+		{
+			name: "field and option lists",
+			src:  ":Author: Me\n:Version: 1\n\n-a, --all  Show all.\n",
+			blocks: []string{
+				"dl/dd/p 1:10 Me",
+				"dl/dd/p 2:11 1",
+				"dl/dd/p 4:12 Show all.",
+			},
+		},
+		{
+			name: "definition lists",
+			src:  "Term\n   Definition.\n\nTerm : classifier\n   Definition.\n",
+			blocks: []string{
+				"dl/dt 1:1 Term",
+				"dl/dd/p 2:4 Definition.",
+				"dl/dt 4:1 Term",
+				"dl/dd/p 5:4 Definition.",
+			},
+		},
+		{
+			name:   "line blocks",
+			src:    "| Line one\n| Line two\n",
+			blocks: []string{"div 1:3 Line one | 2:3 Line two"},
+		},
+		{
+			name: "literal blocks",
+			src:  "Example::\n\n   code here\n\nNext.\n",
+			blocks: []string{
+				"p 1:1 Example:",
+				"pre 3:1    code here",
+				"p 5:1 Next.",
+			},
+		},
+		{
+			name:   "partially minimized literal blocks",
+			src:    "Example ::\n\n   code here\n",
+			blocks: []string{"p 1:1 Example", "pre 3:1    code here"},
+		},
+		{
+			name:   "expanded literal blocks",
+			src:    "::\n\n   code here\n\nNext.\n",
+			blocks: []string{"pre 3:1    code here", "p 5:1 Next."},
+		},
+		{
+			name: "quoted literal blocks",
+			src:  "Quoted::\n\n> quoted literal\n> more\n\nAfter.\n",
+			blocks: []string{
+				"p 1:1 Quoted:",
+				"pre 3:1 > quoted literal | 4:1 > more",
+				"p 6:1 After.",
+			},
+		},
+		{
+			name:   "doctest blocks",
+			src:    ">>> print('very')\nvery\n\nText.\n",
+			blocks: []string{"p 4:1 Text."},
+		},
+		{
+			name: "skipped directives",
+			src: ".. code-block:: python\n\n   very = 1\n\n" +
+				".. raw:: html\n\n   <p>very</p>\n\n" +
+				".. math::\n\n   x\n\nAfter.\n",
+			blocks: []string{"p 13:1 After."},
+		},
+		{
+			name: "admonitions",
+			src:  ".. note:: First line.\n   Second line.\n\n.. admonition:: A title\n\n   Body.\n",
+			blocks: []string{
+				"div/p 1:11 First line. | 2:4 Second line.",
+				"p 4:17 A title",
+				"div/p 6:4 Body.",
+			},
+		},
+		{
+			name:   "version directives",
+			src:    ".. versionadded:: 2.0\n   Added things.\n\n.. deprecated:: 3.0\n",
+			blocks: []string{"div/p 2:4 Added things."},
+		},
+		{
+			name: "images and figures",
+			src:  ".. image:: a.png\n   :alt: Some alt text\n\n.. figure:: b.png\n   :alt: Fig\n\n   Caption.\n",
+			blocks: []string{
+				"attr:alt 2:10 Some alt text",
+				"attr:alt 5:10 Fig",
+				"figure/p 7:4 Caption.",
+			},
+		},
+		{
+			name: "footnotes, targets, comments, and substitutions",
+			src: ".. [1] A footnote.\n.. _target: https://example.com\n" +
+				".. A comment\n   that continues.\n\n.. |sub| replace:: text\n\nText.\n",
+			blocks: []string{
+				"div/p 1:8 A footnote.",
+				"comment 3:3  A comment | 4:1    that continues.",
+				"p 8:1 Text.",
+			},
+		},
+		{
+			name: "grid tables",
+			src: "+------+-------+\n| H1   | H2    |\n+======+=======+\n" +
+				"| a    | b     |\n| c    |       |\n+------+-------+\n" +
+				"| span across  |\n+--------------+\n",
+			blocks: []string{
+				"table/th/p 2:3 H1   ",
+				"table/th/p 2:10 H2    ",
+				"table/td/p 4:3 a     | 5:3 c    ",
+				"table/td/p 4:10 b     ",
+				"table/td/p 7:3 span across  ",
+			},
+		},
+		{
+			name: "simple tables",
+			src:  "=====  =====\nA      B\n=====  =====\nc      d\n       more\ne      f\n=====  =====\n",
+			blocks: []string{
+				"table/th/p 2:1 A      ",
+				"table/th/p 2:8 B",
+				"table/td/p 4:1 c      ",
+				"table/td/p 4:8 d | 5:8 more",
+				"table/td/p 6:1 e      ",
+				"table/td/p 6:8 f",
+			},
+		},
+		{
+			name: "list tables",
+			src:  ".. list-table:: Title\n   :header-rows: 1\n\n   * - H1\n     - H2\n   * - C1\n     - C2\n",
+			blocks: []string{
+				"p 1:17 Title",
+				"table/th/p 4:8 H1",
+				"table/th/p 5:8 H2",
+				"table/td/p 6:8 C1",
+				"table/td/p 7:8 C2",
+			},
+		},
+		{
+			// Columns are byte offsets.
+			name:   "multi-byte characters",
+			src:    "Café\n====\n\n* Ünïcode *item*\n",
+			blocks: []string{"h1 1:1 Café", "ul/li/p 4:3 Ünïcode *item*"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			blocks, err := parseRST(tc.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := describeBlocks(blocks); !reflect.DeepEqual(got, tc.blocks) {
+				t.Errorf("expected:\n%s\ngot:\n%s",
+					strings.Join(tc.blocks, "\n"), strings.Join(got, "\n"))
+			}
+		})
+	}
+}
+
+func TestParseRSTMalformed(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		src    string
+		err    string
+		blocks []string
+	}{
+		{
+			name: "grid table row without a right border",
+			src:  "+-----+-----+\n| a   | b   |\n+-----+-----+\n| c   | d   |\n| e   | f\n+-----+-----+\n\nAfter.\n",
+			err:  "line 4: malformed table: the row doesn't match the table's borders",
+			blocks: []string{
+				"table/td/p 2:3 a   ",
+				"table/td/p 2:9 b   ",
+				"table/td/p 4:3 c    | 5:3 e   ",
+				"p 8:1 After.",
+			},
+		},
+		{
+			name:   "unterminated grid table",
+			src:    "+-----+-----+\n| a   | b   |\n",
+			err:    "line 2: malformed table: the row doesn't match the table's borders",
+			blocks: []string{},
+		},
+		{
+			name:   "grid table row wider than its border",
+			src:    "+-----+\n| a   |\n| too wide |\n+-----+\n",
+			err:    "line 3: malformed table: the line is wider than the table's border",
+			blocks: []string{},
+		},
+		{
+			name:   "unterminated simple table",
+			src:    "=====  =====\nA      B\n\nAfter.\n",
+			err:    "line 1: malformed table: there's no bottom border",
+			blocks: []string{"p 4:1 After."},
+		},
+		{
+			name:   "overline without an underline",
+			src:    "=====\nTitle\n",
+			err:    "line 1: malformed section title: the overline has no matching underline",
+			blocks: []string{"p 2:1 Title"},
+		},
+		{
+			name:   "unterminated inline markup",
+			src:    "*unterminated emphasis and ``code\n",
+			blocks: []string{"p 1:1 *unterminated emphasis and ``code"},
+		},
+		{
+			name:   "empty comment",
+			src:    "..\n",
+			blocks: []string{},
+		},
+		{
+			name:   "literal block at the end",
+			src:    "Text::\n",
+			blocks: []string{"p 1:1 Text:"},
+		},
+		{
+			name:   "empty list item",
+			src:    "- \n",
+			blocks: []string{},
+		},
+		{
+			name:   "empty directive",
+			src:    ".. note::\n",
+			blocks: []string{},
+		},
+		{
+			name:   "empty document",
+			src:    "",
+			blocks: []string{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			blocks, err := parseRST(tc.src)
+			if tc.err == "" && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			} else if tc.err != "" && (err == nil || err.Error() != tc.err) {
+				t.Errorf("expected = %q, got = %v", tc.err, err)
+			}
+			if got := describeBlocks(blocks); !reflect.DeepEqual(got, tc.blocks) {
+				t.Errorf("expected:\n%s\ngot:\n%s",
+					strings.Join(tc.blocks, "\n"), strings.Join(got, "\n"))
+			}
+		})
+	}
+}
+
+func TestParseRSTInline(t *testing.T) {
+	for _, tc := range []struct {
+		raw   string
+		text  string
+		spans []string
+	}{
+		{
+			raw:   "This is *em*, **strong**, and ``code``.",
+			text:  "This is em, strong, and ``****``.",
+			spans: []string{"em:em", "strong:strong", "code:code"},
+		},
+		{
+			raw:   "A :term:`Glossary` and :ref:`a-label` and :ref:`Title <a-label>`.",
+			text:  "A Glossary and ``*******`` and Title.",
+			spans: []string{"a:Glossary", "code:a-label", "a:Title"},
+		},
+		{
+			raw:   "A `title reference` and `Python <https://python.org>`_ and Python_.",
+			text:  "A title reference and Python and Python.",
+			spans: []string{"cite:title reference", "a:Python", "a:Python"},
+		},
+		{
+			raw:   "Custom :kbd:`Ctrl+C` and `text`:sup: and :abbr:`LIFO (last-in, first-out)`.",
+			text:  "Custom ``******`` and text and LIFO (last-in, first-out).",
+			spans: []string{"code:Ctrl+C", "sup:text", "abbr:LIFO (last-in, first-out)"},
+		},
+		{
+			raw:   "Escaped \\*not em\\* and |sub| and [1]_ and _`target`.",
+			text:  "Escaped *not em* and  and  and target.",
+			spans: []string{"span:target"},
+		},
+		{
+			raw:   "Unterminated *em and ``code and `ref.",
+			text:  "Unterminated *em and ``code and `ref.",
+			spans: []string{},
+		},
+		{
+			raw:   "2*3*4 is not em, nor is * alone.",
+			text:  "2*3*4 is not em, nor is * alone.",
+			spans: []string{},
+		},
+	} {
+		in := parseRSTInline(tc.raw, []string{"tt", "code"})
+		if in.text != tc.text {
+			t.Errorf("%q: expected = %q, got = %q", tc.raw, tc.text, in.text)
+		}
+
+		spans := []string{}
+		for _, s := range in.spans {
+			spans = append(spans, s.tag+":"+tc.raw[s.start:s.end])
+		}
+		if !reflect.DeepEqual(spans, tc.spans) {
+			t.Errorf("%q: expected = %v, got = %v", tc.raw, tc.spans, spans)
+		}
+	}
+}
+
+func TestRSTMalformedTable(t *testing.T) {
+	dir := t.TempDir()
+
+	linter := testLinter(t, dir, map[string]string{
+		"a.rst": "+-----+-----+\n| a   | b   |\n| very good\n+-----+-----+\n\nThis is very good.\n",
+	})
+	linter.Manager.Config.InExt = ".txt"
+
+	path := filepath.Join(dir, "a.rst")
+	if _, err := linter.LintFiles([]string{path}); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	// The rest of the document is still linted.
+	linter.Manager.Config.KeepGoing = true
+	linted, err := linter.LintFiles([]string{path})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := linted[0]
+	if !strings.HasPrefix(f.Error, "line 2: malformed table") {
+		t.Errorf("expected a malformed table, got %q", f.Error)
+	} else if len(f.Alerts) != 1 || f.Alerts[0].Line != 6 {
+		t.Errorf("expected 1 alert on line 6, got %v", f.Alerts)
+	}
+}
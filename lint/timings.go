@@ -0,0 +1,119 @@
+package lint
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Timings records the wall time spent on each file and rule.
+type Timings struct {
+	sync.Mutex
+
+	files map[string]*Timing
+	rules map[string]*Timing
+
+	// When profiling, rules also report the time spent matching their
+	// pattern (see `addMatch`).
+	profile bool
+}
+
+// A Timing is the total time spent on a single file or rule.
+type Timing struct {
+	Name     string        // the file's path or rule's name
+	Duration time.Duration // the total (wall) time spent
+	Count    int           // the number of times the file or rule was run
+	Alerts   int           // the number of alerts produced (rules only)
+
+	Pattern     string        // the rule's pattern (profiling only)
+	PatternTime time.Duration // the time spent matching Pattern (profiling only)
+}
+
+func newTimings(profile bool) *Timings {
+	return &Timings{
+		files:   make(map[string]*Timing),
+		rules:   make(map[string]*Timing),
+		profile: profile,
+	}
+}
+
+// Files returns the time spent on each file, slowest first.
+func (t *Timings) Files() []Timing {
+	return t.sorted(t.files)
+}
+
+// Rules returns the time spent on each rule, slowest first.
+//
+// Since rules run concurrently, their total can exceed the total wall time of
+// the lint.
+func (t *Timings) Rules() []Timing {
+	return t.sorted(t.rules)
+}
+
+func (t *Timings) addFile(path string, d time.Duration) {
+	t.add(t.files, path, d, 0)
+}
+
+func (t *Timings) addRule(name string, d time.Duration, alerts int) {
+	t.add(t.rules, name, d, alerts)
+}
+
+// addPattern records the pattern of the named rule (when profiling).
+func (t *Timings) addPattern(name, pattern string) {
+	if !t.profile || pattern == "" {
+		return
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	if _, found := t.rules[name]; !found {
+		t.rules[name] = &Timing{Name: name}
+	}
+	t.rules[name].Pattern = pattern
+}
+
+// addMatch records the time the named rule spent matching its pattern.
+//
+// Rules report this themselves (see `core.File.TimeMatch`), so it's part of
+// -- rather than in addition to -- the time spent in their `Run` method.
+func (t *Timings) addMatch(name string, d time.Duration) {
+	t.Lock()
+	defer t.Unlock()
+
+	if _, found := t.rules[name]; !found {
+		t.rules[name] = &Timing{Name: name}
+	}
+	t.rules[name].PatternTime += d
+}
+
+func (t *Timings) add(m map[string]*Timing, name string, d time.Duration, alerts int) {
+	t.Lock()
+	defer t.Unlock()
+
+	if _, found := m[name]; !found {
+		m[name] = &Timing{Name: name}
+	}
+	m[name].Duration += d
+	m[name].Count++
+	m[name].Alerts += alerts
+}
+
+func (t *Timings) sorted(m map[string]*Timing) []Timing {
+	t.Lock()
+	defer t.Unlock()
+
+	timings := []Timing{}
+	for _, timing := range m {
+		timings = append(timings, *timing)
+	}
+
+	sort.SliceStable(timings, func(i, j int) bool {
+		if timings[i].Duration != timings[j].Duration {
+			return timings[i].Duration > timings[j].Duration
+		}
+		return timings[i].Name < timings[j].Name
+	})
+
+	return timings
+}
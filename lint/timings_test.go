@@ -0,0 +1,49 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/errata-ai/vale/v2/config"
+)
+
+func TestProfilePattern(t *testing.T) {
+	cfg, err := config.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.GBaseStyles = []string{"Vale"}
+	cfg.Profile = true
+	cfg.NoCache = true
+
+	linter, err := NewLinter(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 10; i++ {
+		if _, err = linter.LintString("This is is a test."); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	found := false
+	for _, timing := range linter.Timings.Rules() {
+		if timing.Name != "Vale.Repetition" {
+			continue
+		}
+		found = true
+
+		if timing.Pattern == "" {
+			t.Error("expected the rule's pattern to be recorded")
+		}
+		// The pattern is timed as part of the rule's own run, so it can't
+		// take longer than the run itself.
+		if timing.PatternTime > timing.Duration {
+			t.Errorf("pattern took %v of a %v run", timing.PatternTime, timing.Duration)
+		}
+	}
+
+	if !found {
+		t.Error("expected a timing for 'Vale.Repetition'")
+	}
+}
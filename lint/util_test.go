@@ -0,0 +1,51 @@
+package lint
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/errata-ai/vale/v2/config"
+)
+
+// baseFiles are the configuration and style that our tests build on: a root
+// `.vale.ini` that applies the "Test" style, which has a single rule
+// (`Test.Very`), to every file.
+var baseFiles = map[string]string{
+	".vale.ini":            "StylesPath = styles\n\n[*]\nBasedOnStyles = Test\n",
+	"styles/Test/Very.yml": "extends: existence\nmessage: \"Remove '%s'.\"\nlevel: warning\ntokens:\n  - very\n",
+}
+
+// testLinter writes the base files, followed by files (which may replace
+// them), to dir and returns a Linter whose root configuration (dir/.vale.ini)
+// is based on the "Test" style.
+func testLinter(t *testing.T, dir string, files map[string]string) *Linter {
+	writeFiles(t, dir, baseFiles)
+	writeFiles(t, dir, files)
+
+	cfg, _ := config.New()
+	cfg.Path = filepath.Join(dir, ".vale.ini")
+	cfg.StylesPath = filepath.Join(dir, "styles")
+	cfg.GBaseStyles = []string{"Test"}
+	cfg.Styles = []string{"Test"}
+	cfg.NoCache = true
+
+	linter, err := NewLinter(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return linter
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			t.Fatal(err)
+		} else if err = ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
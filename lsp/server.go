@@ -0,0 +1,349 @@
+/*
+Package lsp implements a Language Server Protocol (LSP) server for Vale.
+
+The server communicates over stdio and holds on to a single `lint.Linter` for
+its entire lifetime, which means that styles are only loaded once (instead of
+once per keystroke). Documents are linted in full on every `didOpen` and
+`didChange` notification and the results are published as diagnostics.
+
+Alerts with a `replace` action are also made available as code actions.
+*/
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/errata-ai/vale/v2/core"
+	"github.com/errata-ai/vale/v2/lint"
+)
+
+// A Server lints documents on behalf of an LSP client.
+type Server struct {
+	linter  *lint.Linter
+	version string
+
+	docs map[string]*document
+	out  io.Writer
+}
+
+type document struct {
+	text        string
+	alerts      []core.Alert
+	diagnostics []diagnostic
+}
+
+// NewServer creates a Server around the given Linter.
+func NewServer(linter *lint.Linter, version string) *Server {
+	return &Server{
+		linter:  linter,
+		version: version,
+		docs:    make(map[string]*document),
+	}
+}
+
+// Serve handles the messages read from `in`, writing any responses to `out`,
+// until the client sends an `exit` notification (or closes `in`).
+func (s *Server) Serve(in io.Reader, out io.Writer) error {
+	s.out = out
+
+	r := bufio.NewReader(in)
+	for {
+		body, err := readMessage(r)
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return core.NewE100("lsp/Serve", err)
+		}
+
+		// A malformed message is the client's problem, so we report it and
+		// keep serving; only failing to write to the client is fatal.
+		var req request
+		if err = json.Unmarshal(body, &req); err != nil {
+			err = s.fail(request{}, parseError, err.Error())
+		} else if req.Method == "exit" {
+			return nil
+		} else {
+			err = s.handle(req)
+		}
+
+		if err != nil {
+			return core.NewE100("lsp/"+req.Method, err)
+		}
+	}
+}
+
+func (s *Server) handle(req request) error {
+	switch req.Method {
+	case "initialize":
+		return s.reply(req, map[string]interface{}{
+			"capabilities": map[string]interface{}{
+				"textDocumentSync": map[string]interface{}{
+					"openClose": true,
+					"change":    1, // Full
+				},
+				"codeActionProvider": true,
+			},
+			"serverInfo": map[string]string{
+				"name":    "vale",
+				"version": s.version,
+			},
+		})
+	case "shutdown":
+		return s.reply(req, nil)
+	case "textDocument/didOpen":
+		var params didOpenParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return s.invalid(req, err)
+		}
+		return s.update(params.TextDocument.URI, params.TextDocument.Text)
+	case "textDocument/didChange":
+		var params didChangeParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return s.invalid(req, err)
+		}
+		n := len(params.ContentChanges)
+		if n == 0 {
+			return nil
+		}
+		// We only support full-document synchronization, so the last change
+		// holds the entire document.
+		return s.update(params.TextDocument.URI, params.ContentChanges[n-1].Text)
+	case "textDocument/didClose":
+		var params didCloseParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return s.invalid(req, err)
+		}
+		delete(s.docs, params.TextDocument.URI)
+		return s.publish(params.TextDocument.URI, []diagnostic{})
+	case "textDocument/codeAction":
+		var params codeActionParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return s.invalid(req, err)
+		}
+		return s.reply(req, s.codeActions(params))
+	default:
+		if req.ID != nil {
+			return s.fail(req, methodNotFound, "method not found: "+req.Method)
+		}
+		// Unknown notifications (e.g., `initialized`) are ignored.
+		return nil
+	}
+}
+
+// update re-lints the document identified by uri and publishes its alerts.
+func (s *Server) update(uri, text string) error {
+	ext := path.Ext(uriToPath(uri))
+	if ext == "" {
+		ext = ".txt"
+	}
+	s.linter.Manager.Config.InExt = ext
+
+	doc := &document{text: text, diagnostics: []diagnostic{}}
+	s.docs[uri] = doc
+
+	linted, err := s.linter.LintString(text)
+	if err != nil {
+		// A single document failing to lint (e.g., a missing external
+		// parser) shouldn't bring down the server, but its previous
+		// diagnostics no longer apply.
+		if err = s.notify("window/logMessage", logMessageParams{
+			Type:    1,
+			Message: core.StripANSI(err.Error()),
+		}); err != nil {
+			return err
+		}
+		return s.publish(uri, doc.diagnostics)
+	}
+
+	lines := strings.Split(core.Sanitize(text), "\n")
+	for _, f := range linted {
+		if f.Error != "" {
+			if err = s.notify("window/logMessage", logMessageParams{
+				Type:    1,
+				Message: f.Error,
+			}); err != nil {
+				return err
+			}
+		}
+		for _, a := range f.SortedAlerts() {
+			doc.alerts = append(doc.alerts, a)
+			doc.diagnostics = append(doc.diagnostics, toDiagnostic(a, lines))
+		}
+	}
+
+	return s.publish(uri, doc.diagnostics)
+}
+
+func (s *Server) codeActions(params codeActionParams) []codeAction {
+	actions := []codeAction{}
+
+	uri := params.TextDocument.URI
+	doc, found := s.docs[uri]
+	if !found {
+		return actions
+	}
+
+	for i, a := range doc.alerts {
+		diag := doc.diagnostics[i]
+		if a.Action.Name != "replace" || !overlaps(diag.Range, params.Range) {
+			continue
+		}
+		for _, suggestion := range a.Action.Params {
+			actions = append(actions, codeAction{
+				Title:       fmt.Sprintf("Replace with '%s'", suggestion),
+				Kind:        "quickfix",
+				Diagnostics: []diagnostic{diag},
+				Edit: workspaceEdit{
+					Changes: map[string][]textEdit{
+						uri: {{Range: diag.Range, NewText: suggestion}},
+					},
+				},
+			})
+		}
+	}
+
+	return actions
+}
+
+func (s *Server) publish(uri string, diagnostics []diagnostic) error {
+	return s.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{
+		URI:         uri,
+		Diagnostics: diagnostics,
+	})
+}
+
+func (s *Server) reply(req request, result interface{}) error {
+	return s.write(response{JSONRPC: "2.0", ID: req.ID, Result: result})
+}
+
+func (s *Server) fail(req request, code int, msg string) error {
+	return s.write(errorResponse{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Error:   responseError{Code: code, Message: msg},
+	})
+}
+
+// invalid reports a message whose params couldn't be read: requests get an
+// error response, while notifications (which can't be answered) are logged.
+func (s *Server) invalid(req request, err error) error {
+	if req.ID != nil {
+		return s.fail(req, invalidParams, err.Error())
+	}
+	return s.notify("window/logMessage", logMessageParams{
+		Type:    1,
+		Message: fmt.Sprintf("%s: %s", req.Method, err.Error()),
+	})
+}
+
+func (s *Server) notify(method string, params interface{}) error {
+	return s.write(notification{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+func (s *Server) write(msg interface{}) error {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(s.out, "Content-Length: %d\r\n\r\n%s", len(b), b)
+	return err
+}
+
+// readMessage reads a single message, including its headers, from r.
+func readMessage(r *bufio.Reader) ([]byte, error) {
+	length := -1
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" {
+			break
+		}
+
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) == 2 && strings.EqualFold(parts[0], "Content-Length") {
+			length, err = strconv.Atoi(strings.TrimSpace(parts[1]))
+			if err != nil {
+				return nil, fmt.Errorf("invalid Content-Length: %s", parts[1])
+			}
+		}
+	}
+
+	if length < 0 {
+		return nil, errors.New("missing Content-Length header")
+	}
+
+	body := make([]byte, length)
+	_, err := io.ReadFull(r, body)
+	return body, err
+}
+
+func toDiagnostic(a core.Alert, lines []string) diagnostic {
+	line := a.Line - 1
+	if line < 0 {
+		line = 0
+	}
+
+	text := ""
+	if line < len(lines) {
+		text = lines[line]
+	}
+
+	severity := severityInformation
+	if a.Severity == "error" {
+		severity = severityError
+	} else if a.Severity == "warning" {
+		severity = severityWarning
+	}
+
+	return diagnostic{
+		Range: textRange{
+			Start: position{Line: line, Character: toUTF16(text, a.Span[0]-1)},
+			End:   position{Line: line, Character: toUTF16(text, a.Span[1])},
+		},
+		Severity: severity,
+		Code:     a.Check,
+		Source:   "vale",
+		Message:  a.Message,
+	}
+}
+
+// toUTF16 converts a rune offset within line into the UTF-16 offset that LSP
+// clients expect.
+func toUTF16(line string, offset int) int {
+	runes := []rune(line)
+	if offset < 0 {
+		offset = 0
+	} else if offset > len(runes) {
+		offset = len(runes)
+	}
+	return len(utf16.Encode(runes[:offset]))
+}
+
+func overlaps(a, b textRange) bool {
+	return !before(a.End, b.Start) && !before(b.End, a.Start)
+}
+
+func before(p, q position) bool {
+	return p.Line < q.Line || (p.Line == q.Line && p.Character < q.Character)
+}
+
+func uriToPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return uri
+	}
+	return u.Path
+}
@@ -0,0 +1,138 @@
+package lsp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/errata-ai/vale/v2/config"
+	"github.com/errata-ai/vale/v2/core"
+	"github.com/errata-ai/vale/v2/lint"
+)
+
+func frame(msg string) string {
+	return fmt.Sprintf("Content-Length: %d\r\n\r\n%s", len(msg), msg)
+}
+
+func TestServe(t *testing.T) {
+	cfg, err := config.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.GBaseStyles = []string{"Vale"}
+
+	linter, err := lint.NewLinter(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var in, out bytes.Buffer
+	in.WriteString(frame(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`))
+	in.WriteString(frame(`{"jsonrpc":"2.0","method":"textDocument/didOpen","params":{"textDocument":{"uri":"file:///tmp/test.md","text":"This is is a test.\n"}}}`))
+	in.WriteString(frame(`{"jsonrpc":"2.0","method":"exit"}`))
+
+	if err = NewServer(linter, "test").Serve(&in, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	r := bufio.NewReader(&out)
+	if _, err = readMessage(r); err != nil {
+		t.Fatal(err)
+	}
+
+	body, err := readMessage(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var published struct {
+		Method string
+		Params publishDiagnosticsParams
+	}
+	if err = json.Unmarshal(body, &published); err != nil {
+		t.Fatal(err)
+	}
+
+	if published.Method != "textDocument/publishDiagnostics" {
+		t.Fatalf("expected diagnostics, got %s", published.Method)
+	} else if len(published.Params.Diagnostics) != 1 {
+		t.Fatalf("expected one diagnostic, got %v", published.Params.Diagnostics)
+	}
+
+	diag := published.Params.Diagnostics[0]
+	if diag.Code != "Vale.Repetition" {
+		t.Errorf("expected = Vale.Repetition, got = %s", diag.Code)
+	} else if diag.Range.Start.Character != 5 || diag.Range.End.Character != 10 {
+		t.Errorf("unexpected range: %v", diag.Range)
+	}
+}
+
+func TestServeMalformed(t *testing.T) {
+	cfg, err := config.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.GBaseStyles = []string{"Vale"}
+
+	linter, err := lint.NewLinter(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var in, out bytes.Buffer
+	in.WriteString(frame(`{"jsonrpc":"2.0","id":1,`))
+	in.WriteString(frame(`{"jsonrpc":"2.0","id":2,"method":"textDocument/codeAction","params":[]}`))
+	in.WriteString(frame(`{"jsonrpc":"2.0","method":"textDocument/didOpen","params":{"textDocument":5}}`))
+	in.WriteString(frame(`{"jsonrpc":"2.0","method":"textDocument/didOpen","params":{"textDocument":{"uri":"file:///tmp/test.md","text":"This is is a test.\n"}}}`))
+	in.WriteString(frame(`{"jsonrpc":"2.0","method":"exit"}`))
+
+	// None of the malformed messages should stop the server.
+	if err = NewServer(linter, "test").Serve(&in, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"error:-32700",
+		"error:-32602",
+		"window/logMessage",
+		"textDocument/publishDiagnostics",
+	}
+
+	r := bufio.NewReader(&out)
+	for _, want := range expected {
+		body, err := readMessage(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var msg struct {
+			Method string
+			Error  *responseError
+		}
+		if err = json.Unmarshal(body, &msg); err != nil {
+			t.Fatal(err)
+		}
+
+		got := msg.Method
+		if msg.Error != nil {
+			got = fmt.Sprintf("error:%d", msg.Error.Code)
+		}
+		if got != want {
+			t.Errorf("expected = %s, got = %s", want, got)
+		}
+	}
+}
+
+func TestToDiagnostic(t *testing.T) {
+	a := core.Alert{Line: 1, Span: []int{4, 7}, Severity: "error"}
+
+	diag := toDiagnostic(a, strings.Split("😀 a test", "\n"))
+	if diag.Range.Start.Character != 4 || diag.Range.End.Character != 8 {
+		t.Errorf("unexpected range: %v", diag.Range)
+	} else if diag.Severity != severityError {
+		t.Errorf("expected = %d, got = %d", severityError, diag.Severity)
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -8,6 +9,7 @@ import (
 	"github.com/errata-ai/vale/v2/config"
 	"github.com/errata-ai/vale/v2/core"
 	"github.com/errata-ai/vale/v2/lint"
+	"github.com/errata-ai/vale/v2/lsp"
 	"github.com/errata-ai/vale/v2/source"
 	"github.com/errata-ai/vale/v2/ui"
 	"github.com/urfave/cli"
@@ -21,10 +23,64 @@ func validateFlags(config *config.Config) error {
 		return core.NewE100(
 			"--config",
 			fmt.Errorf("path '%s' does not exist", config.Path))
+	} else if config.Jobs < 0 {
+		return core.NewE100(
+			"--jobs",
+			fmt.Errorf("'%d' is not a positive integer", config.Jobs))
+	} else if config.FilesFrom == "-" && config.Diff == "-" {
+		return core.NewE100(
+			"--files-from",
+			errors.New("'--diff=-' also reads from stdin"))
+	} else if config.Staged && (config.FilesFrom != "" || config.Diff != "") {
+		return core.NewE100(
+			"--staged",
+			errors.New("can't be combined with '--files-from' or '--diff'"))
+	} else if config.Staged && (config.Fix || config.DryRun) {
+		return core.NewE100(
+			"--staged",
+			errors.New("fixes can't be applied to the index"))
+	} else if config.Rev != "" && (config.Staged || config.FilesFrom != "" || config.Diff != "") {
+		return core.NewE100(
+			"--rev",
+			errors.New("can't be combined with '--staged', '--files-from', or '--diff'"))
+	} else if config.Rev != "" && (config.Fix || config.DryRun) {
+		return core.NewE100(
+			"--rev",
+			errors.New("fixes can't be applied to a revision"))
 	}
 	return nil
 }
 
+// keepGoing determines if we should report the files that can't be linted
+// and keep going (rather than stopping at the first one).
+//
+// Unless `--on-error` says otherwise, we do so when linting directories or
+// lists of files (rather than explicitly-named files).
+func keepGoing(c *cli.Context, config *config.Config, onError string) (bool, error) {
+	switch onError {
+	case "report":
+		return true, nil
+	case "stop":
+		return false, nil
+	case "":
+		if config.FilesFrom != "" || config.Staged || config.Rev != "" {
+			return true, nil
+		} else if config.Diff != "" && c.NArg() == 0 {
+			return true, nil
+		}
+		for _, arg := range c.Args() {
+			if core.IsDir(arg) {
+				return true, nil
+			}
+		}
+		return false, nil
+	default:
+		return false, core.NewE100(
+			"--on-error",
+			fmt.Errorf("'%s' must be 'report' or 'stop'", onError))
+	}
+}
+
 func stat() bool {
 	stat, err := os.Stdin.Stat()
 	if err != nil || (stat.Mode()&os.ModeCharDevice) != 0 {
@@ -33,6 +89,13 @@ func stat() bool {
 	return true
 }
 
+// hasInput determines if we were given anything to lint: file or directory
+// arguments, a list of files (`--files-from`), or a git source (`--staged` or
+// `--rev`).
+func hasInput(c *cli.Context, config *config.Config) bool {
+	return c.NArg() > 0 || config.FilesFrom != "" || config.Staged || config.Rev != ""
+}
+
 func looksLikeStdin(s string) bool {
 	return !(core.FileExists(s) || core.IsDir(s)) && s != ""
 }
@@ -78,8 +141,9 @@ func doLint(c *cli.Context, l *lint.Linter, glob string) ([]*core.File, error) {
 }
 
 func main() {
-	var glob string
+	var glob, onError string
 	var hasErrors bool
+	var failed int
 
 	config, err := config.New()
 	if err != nil {
@@ -121,7 +185,7 @@ func main() {
 		cli.StringFlag{
 			Name:        "output",
 			Value:       "CLI",
-			Usage:       `output style ("line" or "JSON")`,
+			Usage:       `output style ("line", "JSON", "sarif", "junit", or "checkstyle")`,
 			Destination: &config.Output,
 		},
 		cli.StringFlag{
@@ -137,7 +201,7 @@ func main() {
 		},
 		cli.BoolFlag{
 			Name:        "no-exit",
-			Usage:       "don't return a nonzero exit code on lint errors",
+			Usage:       "don't return a nonzero exit code on lint errors or failed files",
 			Destination: &config.NoExit,
 		},
 		cli.BoolFlag{
@@ -155,52 +219,321 @@ func main() {
 			Usage:       "lint all files line-by-line",
 			Destination: &config.Simple,
 		},
+		cli.StringFlag{
+			Name:        "files-from",
+			Usage:       `lint the files listed (one per line, or NUL-separated) in the given file ("-" reads the list from stdin)`,
+			Destination: &config.FilesFrom,
+		},
+		cli.BoolFlag{
+			Name:        "staged",
+			Usage:       "lint the staged versions of the files in git's index (e.g., in a pre-commit hook)",
+			Destination: &config.Staged,
+		},
+		cli.StringFlag{
+			Name:        "rev",
+			Usage:       "lint the files as they exist at the given git revision (without checking it out)",
+			Destination: &config.Rev,
+		},
+		cli.StringFlag{
+			Name:        "on-error",
+			Usage:       `what to do when a file can't be linted: "report" it and keep going (the default for directories) or "stop"`,
+			Destination: &onError,
+		},
+		cli.IntFlag{
+			Name:        "jobs",
+			Usage:       "the number of files (and rules) to process in parallel",
+			Destination: &config.Jobs,
+		},
+		cli.BoolFlag{
+			Name:        "timings",
+			Usage:       "report the time spent on each file and rule (to stderr); implies --no-cache",
+			Destination: &config.Timings,
+		},
+		cli.BoolFlag{
+			Name:        "no-cache",
+			Usage:       "don't use (or update) the results cache",
+			Destination: &config.NoCache,
+		},
 		cli.BoolFlag{
 			Name:        "relative",
 			Usage:       "return relative paths",
 			Destination: &config.Relative,
 		},
-	}
-
-	app.Commands = []cli.Command{
-		{
-			Name:    "ls-config",
-			Aliases: []string{"dc"},
-			Usage:   "List the current configuration options",
-			Action: func(c *cli.Context) error {
-				err := source.From("ini", config)
-				fmt.Println(config.String())
-				return err
-			},
+		cli.StringFlag{
+			Name:        "baseline",
+			Usage:       `only report alerts that aren't in the given baseline file`,
+			Destination: &config.Baseline,
+		},
+		cli.StringFlag{
+			Name:        "diff",
+			Usage:       `only report alerts on lines changed since the given git revision ("-" reads a unified diff from stdin)`,
+			Destination: &config.Diff,
+		},
+		cli.BoolFlag{
+			Name:        "fix",
+			Usage:       "apply the suggested fixes in place",
+			Destination: &config.Fix,
+		},
+		cli.BoolFlag{
+			Name:        "dry-run",
+			Usage:       "print the suggested fixes as a unified diff",
+			Destination: &config.DryRun,
 		},
 	}
 
-	app.Action = func(c *cli.Context) error {
-		if c.NArg() == 0 && !stat() {
-			return cli.ShowAppHelp(c)
-		} else if err := validateFlags(config); err != nil {
+	run := func(c *cli.Context) error {
+		if err := validateFlags(config); err != nil {
+			return err
+		} else if config.KeepGoing, err = keepGoing(c, config, onError); err != nil {
 			return err
 		} else if err = source.From("ini", config); err != nil {
 			return err
 		}
 
+		if config.Timings {
+			// Cached files don't run any rules.
+			config.NoCache = true
+		}
+
 		linter, err := lint.NewLinter(config)
 		if err != nil {
 			return err
 		}
+		defer linter.Manager.Close()
+
+		if config.Timings {
+			defer ui.PrintTimings(os.Stderr, linter.Timings)
+		}
 
-		linted, err := doLint(c, linter, glob)
+		var changed core.ChangedLines
+		if config.Diff != "" {
+			if changed, err = readDiff(config.Diff); err != nil {
+				return err
+			}
+		}
+
+		var linted []*core.File
+		if config.FilesFrom != "" {
+			if c.NArg() > 0 {
+				return core.NewE100(
+					"--files-from",
+					errors.New("can't be combined with file or directory arguments"))
+			}
+
+			var paths []string
+			if paths, err = readFileList(config.FilesFrom); err != nil {
+				return err
+			}
+			linted, err = linter.LintFiles(paths)
+		} else if config.Staged {
+			if c.NArg() > 0 {
+				return core.NewE100(
+					"--staged",
+					errors.New("can't be combined with file or directory arguments"))
+			}
+			linted, err = lintStaged(linter, glob)
+		} else if config.Rev != "" {
+			if c.NArg() > 0 {
+				return core.NewE100(
+					"--rev",
+					errors.New("can't be combined with file or directory arguments"))
+			}
+			linted, err = lintRev(linter, glob, config.Rev)
+		} else if changed != nil && c.NArg() == 0 {
+			// Lint all of the files touched by the diff.
+			linted, err = linter.Lint(existing(changed.Paths()), glob)
+		} else {
+			linted, err = doLint(c, linter, glob)
+		}
 		if err != nil {
 			return err
 		}
 
-		hasErrors, err = ui.PrintAlerts(linted, config)
+		if changed != nil {
+			changed.Filter(linted)
+		}
+		failed = ui.CountFailed(linted)
+
+		if config.UpdateBaseline {
+			return writeBaseline(linted, config)
+		} else if config.Baseline != "" {
+			if err = filterBaseline(linted, config); err != nil {
+				return err
+			}
+		}
+
+		if config.Profile {
+			ui.PrintProfile(os.Stdout, linter.Timings)
+			return nil
+		}
+
+		if config.Fix || config.DryRun {
+			if err = fixFiles(linted, config); err != nil {
+				return err
+			} else if config.DryRun {
+				return nil
+			}
+		}
+
+		hasErrors, err = ui.PrintAlerts(linted, config, linter.Manager.Rules())
 		return err
 	}
 
+	app.Commands = []cli.Command{
+		{
+			Name:    "ls-config",
+			Aliases: []string{"dc"},
+			Usage:   "List the current configuration options",
+			Action: func(c *cli.Context) error {
+				err := source.From("ini", config)
+				fmt.Println(config.String())
+				return err
+			},
+		},
+		{
+			Name:      "ls-rules",
+			Usage:     "List the rules that apply to a file (and why)",
+			ArgsUsage: "<file>",
+			Action: func(c *cli.Context) error {
+				if c.NArg() != 1 {
+					return cli.ShowCommandHelp(c, "ls-rules")
+				} else if err := validateFlags(config); err != nil {
+					return err
+				} else if err = source.From("ini", config); err != nil {
+					return err
+				}
+
+				path := c.Args()[0]
+				if !core.FileExists(path) || core.IsDir(path) {
+					return core.NewE100(
+						"ls-rules",
+						fmt.Errorf("'%s' is not a file", path))
+				}
+
+				linter, err := lint.NewLinter(config)
+				if err != nil {
+					return err
+				}
+				defer linter.Manager.Close()
+
+				exp, err := linter.Explain(path)
+				if err != nil {
+					return err
+				}
+
+				ui.PrintRules(os.Stdout, exp, config.Output)
+				return nil
+			},
+		},
+		{
+			Name:      "validate",
+			Usage:     "Check the rule definitions on StylesPath for errors",
+			ArgsUsage: "[StylesPath]",
+			Action: func(c *cli.Context) error {
+				root := c.Args().First()
+				if root == "" {
+					if err := validateFlags(config); err != nil {
+						return err
+					} else if err = source.From("ini", config); err != nil {
+						return err
+					}
+					root = config.StylesPath
+				}
+
+				invalid, err := validateStyles(root, config)
+				hasErrors = invalid > 0
+				return err
+			},
+		},
+		{
+			Name:  "ls",
+			Usage: "Start a Language Server Protocol server over stdio",
+			Action: func(c *cli.Context) error {
+				if err := validateFlags(config); err != nil {
+					return err
+				} else if err = source.From("ini", config); err != nil {
+					return err
+				}
+				// A document that can't be fully linted (e.g., because a
+				// plugin failed) still has the rest of its alerts published.
+				config.KeepGoing = true
+
+				linter, err := lint.NewLinter(config)
+				if err != nil {
+					return err
+				}
+				defer linter.Manager.Close()
+
+				return lsp.NewServer(linter, version).Serve(os.Stdin, os.Stdout)
+			},
+		},
+		{
+			Name:      "baseline",
+			Usage:     "Record the current alerts in a baseline file",
+			ArgsUsage: "[file or directory ...]",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:        "baseline",
+					Usage:       `the baseline file to (re-)generate (default: "` + defaultBaseline + `")`,
+					Destination: &config.Baseline,
+				},
+			},
+			Action: func(c *cli.Context) error {
+				if !hasInput(c, config) {
+					return cli.ShowCommandHelp(c, "baseline")
+				}
+				config.UpdateBaseline = true
+				return run(c)
+			},
+		},
+		{
+			Name:      "profile",
+			Usage:     "Report the time spent in (and alerts produced by) each rule",
+			ArgsUsage: "[file or directory ...]",
+			Action: func(c *cli.Context) error {
+				if !hasInput(c, config) {
+					return cli.ShowCommandHelp(c, "profile")
+				}
+				// Cached files don't run any rules.
+				config.NoCache = true
+				config.Profile = true
+				return run(c)
+			},
+		},
+		{
+			Name:      "fix",
+			Usage:     "Apply the suggested fixes to the given files",
+			ArgsUsage: "[file or directory ...]",
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:        "dry-run",
+					Usage:       "print the suggested fixes as a unified diff",
+					Destination: &config.DryRun,
+				},
+			},
+			Action: func(c *cli.Context) error {
+				if !hasInput(c, config) {
+					return cli.ShowCommandHelp(c, "fix")
+				}
+				config.Fix = true
+				return run(c)
+			},
+		},
+	}
+
+	app.Action = func(c *cli.Context) error {
+		if !hasInput(c, config) && !stat() && config.Diff == "" {
+			return cli.ShowAppHelp(c)
+		}
+		return run(c)
+	}
+
 	if err = app.Run(os.Args); err != nil {
 		ui.ShowError(err, config.Output, os.Stderr)
 		os.Exit(2)
+	} else if failed > 0 && !config.NoExit {
+		// Some files couldn't be linted (see `--on-error`).
+		os.Exit(3)
 	} else if hasErrors && !config.NoExit {
 		os.Exit(1)
 	}
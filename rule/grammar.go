@@ -57,13 +57,17 @@ type LTResult struct {
 	Matches  []match  `json:"matches"`
 }
 
+// ltTimeout is how long, in seconds, we wait for a response from LanguageTool
+// if `ProcessTimeout` hasn't been set.
+const ltTimeout = 2
+
 // CheckWithLT interfaces with a running instace of LanguageTool.
 //
 // TODO: How do we speed this up?
 func CheckWithLT(text string, f *core.File, cfg *config.Config) ([]core.Alert, error) {
 	alerts := []core.Alert{}
 
-	resp, err := checkWithURL(text, "en-US", cfg.LTPath, cfg.Timeout)
+	resp, err := checkWithURL(text, "en-US", cfg.LTPath, cfg.ProcessTimeout(ltTimeout))
 	if err != nil {
 		return alerts, err
 	}
@@ -0,0 +1,172 @@
+package source
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/errata-ai/vale/v2/core"
+	"gopkg.in/ini.v1"
+)
+
+// pathKeys are the core settings whose (relative) values are resolved
+// against the location of the file that defines them.
+var pathKeys = []string{"StylesPath", "SphinxBuildPath"}
+
+// extend resolves the `Extends` key of the configuration f, which was loaded
+// from path.
+//
+// `Extends` is a list of other configuration files (INI, YAML, or TOML) that f
+// builds upon. They're loaded recursively and applied in order, which means
+// that later files take precedence over earlier ones and f takes precedence
+// over all of them. A key that's defined in more than one file is replaced,
+// not merged.
+//
+// We also return the origin of each effective setting, keyed by its name
+// (e.g., "StylesPath" or "[*.md] BasedOnStyles").
+func extend(f *ini.File, path string, seen []string) (*ini.File, map[string]string, error) {
+	origins := map[string]string{}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return f, origins, core.NewE100("extend", err)
+	}
+
+	parents := []string{}
+	if sec := f.Section(""); sec.HasKey("Extends") {
+		parents = mergeValues(sec.Key("Extends").ValueWithShadows())
+	}
+	if len(seen) > 0 {
+		// This file is being merged into another one.
+		absolutize(f, path)
+	}
+	seen = append(seen, abs)
+
+	if len(parents) == 0 {
+		for _, sec := range f.Sections() {
+			for _, key := range sec.Keys() {
+				origins[originLabel(sec.Name(), key.Name())] = abs
+			}
+		}
+		return f, origins, nil
+	}
+
+	merged, err := ini.LoadSources(ini.LoadOptions{
+		AllowShadows:             true,
+		SpaceBeforeInlineComment: true}, []byte(""))
+	if err != nil {
+		return f, origins, core.NewE100("extend", err)
+	}
+
+	for _, entry := range parents {
+		parent := resolveExtends(entry, path)
+		if !core.FileExists(parent) {
+			return f, origins, core.NewE201FromTarget(
+				fmt.Sprintf("The path '%s' does not exist.", parent),
+				entry,
+				path)
+		} else if core.StringInSlice(parent, seen) {
+			return f, origins, core.NewE201FromTarget(
+				fmt.Sprintf("'%s' can't be extended; it would create a cycle: %s.",
+					entry, strings.Join(append(seen, parent), " -> ")),
+				entry,
+				path)
+		}
+
+		pf, err := shadowLoad(parent)
+		if fe, ok := err.(formatError); ok {
+			return f, origins, fe.error
+		} else if err != nil {
+			return f, origins, core.NewE100(parent, err)
+		}
+
+		pf, parentOrigins, err := extend(pf, parent, seen)
+		if err != nil {
+			return f, origins, err
+		}
+		overlay(merged, pf, parentOrigins, origins, "")
+	}
+
+	f.Section("").DeleteKey("Extends")
+	overlay(merged, f, nil, origins, abs)
+
+	return merged, origins, nil
+}
+
+// overlay copies every key in src to dst, replacing any existing values.
+//
+// The origin of each copied key is taken from srcOrigins or, if that's nil,
+// is set to path.
+func overlay(dst, src *ini.File, srcOrigins, origins map[string]string, path string) {
+	for _, sec := range src.Sections() {
+		target := dst.Section(sec.Name())
+		for _, key := range sec.Keys() {
+			name := key.Name()
+			setKey(target, name, key.ValueWithShadows())
+
+			label := originLabel(sec.Name(), name)
+			if srcOrigins != nil {
+				origins[label] = srcOrigins[label]
+			} else {
+				origins[label] = path
+			}
+		}
+	}
+}
+
+// absolutize resolves f's relative paths against path's directory, so that
+// they're still correct after f has been merged into another file.
+func absolutize(f *ini.File, path string) {
+	sec := f.Section("")
+	for _, name := range pathKeys {
+		if !sec.HasKey(name) {
+			continue
+		}
+
+		values := []string{}
+		for _, value := range sec.Key(name).ValueWithShadows() {
+			if value != "" {
+				value = determinePath(path, filepath.FromSlash(value))
+			}
+			values = append(values, value)
+		}
+		setKey(sec, name, values)
+	}
+}
+
+// setKey replaces the value(s) of sec's key, name.
+func setKey(sec *ini.Section, name string, values []string) {
+	sec.DeleteKey(name)
+	for i, value := range values {
+		if i == 0 {
+			sec.NewKey(name, value)
+		} else {
+			sec.Key(name).AddShadow(value)
+		}
+	}
+}
+
+// resolveExtends converts an `Extends` entry into an absolute path.
+func resolveExtends(entry, path string) string {
+	entry = filepath.FromSlash(entry)
+	if strings.HasPrefix(entry, "~") {
+		if home, err := os.UserHomeDir(); err == nil {
+			entry = filepath.Join(home, entry[1:])
+		}
+	}
+
+	resolved := determinePath(path, entry)
+	if abs, err := filepath.Abs(resolved); err == nil {
+		return abs
+	}
+
+	return resolved
+}
+
+func originLabel(section, key string) string {
+	if section == ini.DefaultSection {
+		return key
+	}
+	return fmt.Sprintf("[%s] %s", section, key)
+}
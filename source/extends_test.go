@@ -0,0 +1,61 @@
+package source
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func writeConfig(t *testing.T, path, content string) {
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExtends(t *testing.T) {
+	dir := t.TempDir()
+
+	base := filepath.Join(dir, "base.ini")
+	writeConfig(t, base, "StylesPath = styles\nMinAlertLevel = error\n\n[*.md]\nBasedOnStyles = Vale\n")
+
+	extra := filepath.Join(dir, "extra.toml")
+	writeConfig(t, extra, "[\"*.md\"]\nBasedOnStyles = [\"Vale\", \"Extra\"]\n")
+
+	child := filepath.Join(dir, ".vale.ini")
+	writeConfig(t, child, "Extends = base.ini, extra.toml\nMinAlertLevel = suggestion\n")
+
+	f, err := shadowLoad(child)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	merged, origins, err := extend(f, child, []string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v := merged.Section("").Key("MinAlertLevel").String(); v != "suggestion" {
+		t.Errorf("expected the child to take precedence, got '%s'", v)
+	}
+
+	styles := merged.Section("*.md").Key("BasedOnStyles").ValueWithShadows()
+	if len(styles) != 2 || styles[1] != "Extra" {
+		t.Errorf("expected the last parent to take precedence, got %v", styles)
+	}
+
+	expected := filepath.Join(dir, "styles")
+	if v := merged.Section("").Key("StylesPath").String(); v != expected {
+		t.Errorf("expected = %s, got = %s", expected, v)
+	}
+
+	if origins["StylesPath"] != base || origins["[*.md] BasedOnStyles"] != extra {
+		t.Errorf("unexpected origins: %v", origins)
+	}
+
+	writeConfig(t, base, "Extends = .vale.ini\n")
+	if f, err = shadowLoad(child); err != nil {
+		t.Fatal(err)
+	} else if _, _, err = extend(f, child, []string{}); err == nil {
+		t.Error("expected a cycle to be detected")
+	}
+}
@@ -0,0 +1,140 @@
+package source
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/errata-ai/vale/v2/core"
+	"gopkg.in/ini.v1"
+)
+
+// A configEntry is a single key-value pair from a non-INI configuration file.
+type configEntry struct {
+	section string   // the section (glob) name; "" for the top level
+	key     string   // the key's name
+	values  []string // multiple values become shadowed keys
+}
+
+// A formatError is an error that has already been formatted for the user
+// (e.g., an E201 pointing into a YAML file).
+type formatError struct {
+	error
+}
+
+// converters translate non-INI configuration files into INI.
+//
+// Both formats mirror the structure of an INI file: top-level keys are core
+// settings, nested tables (or mappings) are sections, and lists become
+// shadowed keys.
+var converters = map[string]func(path string) ([]configEntry, error){
+	".yaml": yamlEntries,
+	".yml":  yamlEntries,
+	".toml": tomlEntries,
+}
+
+// configNames are the file names that we search for, in order of priority.
+var configNames = []string{
+	".vale", "_vale", "vale.ini", ".vale.ini", "_vale.ini",
+	".vale.yaml", "_vale.yaml", ".vale.yml", "_vale.yml",
+	".vale.toml", "_vale.toml",
+	""}
+
+// namesWithExt returns the config names that use one of the given extensions.
+func namesWithExt(exts ...string) []string {
+	names := []string{}
+	for _, name := range configNames {
+		if name == "" || core.StringInSlice(filepath.Ext(name), exts) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// toSources converts any non-INI paths in sources into their INI equivalent.
+func toSources(sources ...interface{}) ([]interface{}, error) {
+	converted := make([]interface{}, len(sources))
+	for i, source := range sources {
+		converted[i] = source
+
+		path, ok := source.(string)
+		if !ok {
+			continue
+		}
+
+		ext := strings.ToLower(filepath.Ext(path))
+		if convert, found := converters[ext]; found && core.FileExists(path) {
+			entries, err := convert(path)
+			if err != nil {
+				return converted, formatError{err}
+			}
+
+			data, err := entriesToINI(entries)
+			if err != nil {
+				return converted, err
+			}
+			converted[i] = data
+		}
+	}
+	return converted, nil
+}
+
+func invalidValue(key string) string {
+	return fmt.Sprintf("'%s' must be a string, number, boolean, or list.", key)
+}
+
+// entryValues converts a decoded (scalar or list) value into its INI values.
+func entryValues(value interface{}) ([]string, bool) {
+	items, ok := value.([]interface{})
+	if !ok {
+		items = []interface{}{value}
+	}
+
+	values := []string{}
+	for _, item := range items {
+		switch v := item.(type) {
+		case bool:
+			// Both formats use booleans for INI's `YES` and `NO`.
+			if v {
+				values = append(values, "YES")
+			} else {
+				values = append(values, "NO")
+			}
+		case string, int, int64, uint64, float64:
+			values = append(values, fmt.Sprint(v))
+		case nil:
+			values = append(values, "")
+		default:
+			return values, false
+		}
+	}
+
+	return values, true
+}
+
+func entriesToINI(entries []configEntry) ([]byte, error) {
+	var buf bytes.Buffer
+
+	f, err := ini.LoadSources(ini.LoadOptions{AllowShadows: true}, []byte(""))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		sec := f.Section(entry.section)
+
+		for i, value := range entry.values {
+			if i == 0 {
+				if _, err = sec.NewKey(entry.key, value); err != nil {
+					return nil, err
+				}
+			} else if err = sec.Key(entry.key).AddShadow(value); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	_, err = f.WriteTo(&buf)
+	return buf.Bytes(), err
+}
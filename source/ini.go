@@ -46,7 +46,33 @@ var syntaxOpts = map[string]func(string, *ini.Section, *config.Config) error{
 		return nil
 	},
 	"Parser": func(label string, sec *ini.Section, cfg *config.Config) error {
-		cfg.Parsers[label] = sec.Key("Parser").String()
+		parser := strings.ToLower(sec.Key("Parser").String())
+		if !core.StringInSlice(parser, []string{"html", "markdown", "rst2html", "asciidoctor"}) {
+			return core.NewE201FromTarget(
+				"Parser must be 'html', 'markdown', 'rst2html', or 'asciidoctor'.",
+				sec.Key("Parser").String(),
+				cfg.Path)
+		}
+		if format, found := parserFormats[parser]; found {
+			// These parsers are only for the formats that we otherwise parse
+			// ourselves, not a Command's output.
+			if sec.HasKey("Command") {
+				return core.NewE201FromTarget(
+					fmt.Sprintf("Parser '%s' can't be used with a Command.", parser),
+					sec.Key("Parser").String(),
+					cfg.Path)
+			} else if !matchesFormat(label, format, cfg) {
+				return core.NewE201FromTarget(
+					fmt.Sprintf("Parser '%s' only applies to '.%s' files.", parser, format),
+					sec.Key("Parser").String(),
+					cfg.Path)
+			}
+		}
+		cfg.Parsers[label] = parser
+		return nil
+	},
+	"Command": func(label string, sec *ini.Section, cfg *config.Config) error {
+		cfg.Commands[label] = sec.Key("Command").String()
 		return nil
 	},
 	"Transform": func(label string, sec *ini.Section, cfg *config.Config) error {
@@ -63,11 +89,60 @@ var syntaxOpts = map[string]func(string, *ini.Section, *config.Config) error{
 	},
 }
 
+// parserFormats are the formats that each of the external parsers (which
+// replace our own) is for.
+var parserFormats = map[string]string{
+	"asciidoctor": "adoc",
+	"rst2html":    "rst",
+}
+
+// matchesFormat determines if the section given by label could match a file
+// of the given format (including any extensions associated with it in
+// `[formats]`).
+//
+// Since we can't list every path that a glob matches, we accept a section
+// that mentions one of the extensions or that matches a file with one of
+// them in (or below) the section's leading directory -- e.g., "docs/*".
+func matchesFormat(label, format string, cfg *config.Config) bool {
+	exts := []string{format}
+	for ext, normed := range cfg.Formats {
+		if normed == format {
+			exts = append(exts, ext)
+		}
+	}
+
+	prefix := label
+	if i := strings.IndexAny(label, "*?[{"); i >= 0 {
+		prefix = label[:i]
+	}
+
+	pat := cfg.SecToPat[label]
+	for _, ext := range exts {
+		if strings.Contains(label, ext) {
+			return true
+		} else if pat != nil && (pat.Match(prefix+"a."+ext) || pat.Match(prefix+"a/a."+ext)) {
+			return true
+		}
+	}
+
+	return false
+}
+
 var globalOpts = map[string]func(*ini.Section, *config.Config, []string){
 	"BasedOnStyles": func(sec *ini.Section, cfg *config.Config, args []string) {
 		cfg.GBaseStyles = mergeValues(sec.Key("BasedOnStyles").ValueWithShadows())
 		cfg.Styles = append(cfg.Styles, cfg.GBaseStyles...)
 	},
+	"Exclude": func(sec *ini.Section, cfg *config.Config, args []string) {
+		// Unlike other lists, patterns can't be comma-separated: commas are
+		// part of the glob syntax (e.g., `{vendor,build}/**`).
+		cfg.Exclude = []string{}
+		for _, pat := range sec.Key("Exclude").ValueWithShadows() {
+			if pat = strings.TrimSpace(pat); pat != "" {
+				cfg.Exclude = append(cfg.Exclude, pat)
+			}
+		}
+	},
 	"IgnorePatterns": func(sec *ini.Section, cfg *config.Config, args []string) {
 		cfg.BlockIgnores["*"] = mergeValues(sec.Key("IgnorePatterns").ValueWithShadows())
 	},
@@ -165,6 +240,19 @@ var coreOpts = map[string]func(*ini.Section, *config.Config, []string) error{
 		cfg.SphinxAuto = sec.Key("SphinxAutoBuild").MustString("")
 		return nil
 	},
+	"Concurrency": func(sec *ini.Section, cfg *config.Config, args []string) error {
+		if cfg.Jobs <= 0 {
+			n := sec.Key("Concurrency").MustInt()
+			if n < 1 {
+				return core.NewE201FromTarget(
+					"Concurrency must be a positive integer.",
+					"Concurrency",
+					cfg.Path)
+			}
+			cfg.Concurrency = n
+		}
+		return nil
+	},
 	"ProcessTimeout": func(sec *ini.Section, cfg *config.Config, args []string) error {
 		cfg.Timeout = sec.Key("ProcessTimeout").MustInt()
 		return nil
@@ -172,20 +260,21 @@ var coreOpts = map[string]func(*ini.Section, *config.Config, []string) error{
 }
 
 func shadowLoad(source interface{}, others ...interface{}) (*ini.File, error) {
+	sources, err := toSources(append([]interface{}{source}, others...)...)
+	if err != nil {
+		return nil, err
+	}
 	return ini.LoadSources(ini.LoadOptions{
 		AllowShadows:             true,
-		SpaceBeforeInlineComment: true}, source, others...)
+		SpaceBeforeInlineComment: true}, sources[0], sources[1:]...)
 }
 
-func loadINI(cfg *config.Config) error {
+func loadINI(cfg *config.Config, names []string) error {
 	var base string
 	var uCfg *ini.File
 	var err error
 	var sources []string
 
-	names := []string{
-		".vale", "_vale", "vale.ini", ".vale.ini", "_vale.ini", ""}
-
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return core.NewE100("loadINI/homedir", err)
@@ -214,12 +303,23 @@ func loadINI(cfg *config.Config) error {
 		cfg.Path = base
 	}
 
-	if err != nil {
+	if fe, ok := err.(formatError); ok {
+		return fe.error
+	} else if err != nil {
 		return core.NewE100(".vale.ini", err)
+	}
+
+	uCfg, cfg.Origins, err = extend(uCfg, cfg.Path, []string{})
+	if err != nil {
+		return err
 	} else if core.StringInSlice(cfg.AlertLevel, core.AlertLevels) {
 		cfg.MinAlertLevel = core.LevelToInt[cfg.AlertLevel]
 	}
 
+	if cfg.Jobs > 0 {
+		cfg.Concurrency = cfg.Jobs
+	}
+
 	uCfg.BlockMode = false
 	return processConfig(uCfg, cfg, sources)
 }
@@ -268,16 +368,24 @@ func processSources(cfg *config.Config, sources []string) (*ini.File, error) {
 		return uCfg, errors.New("no sources provided")
 	} else if len(sources) == 1 {
 		cfg.Path = sources[0]
-		return ini.Load(cfg.Path)
+		converted, err := toSources(cfg.Path)
+		if err != nil {
+			return uCfg, err
+		}
+		return ini.Load(converted[0])
 	}
 
-	t := sources[1:]
-	s := make([]interface{}, len(t))
-	for i, v := range t {
+	s := make([]interface{}, len(sources))
+	for i, v := range sources {
 		s[i] = v
 	}
 
-	uCfg, err = ini.Load(sources[0], s...)
+	converted, err := toSources(s...)
+	if err != nil {
+		return uCfg, err
+	}
+
+	uCfg, err = ini.Load(converted[0], converted[1:]...)
 	cfg.Path = sources[len(sources)-1]
 
 	return uCfg, err
@@ -306,6 +414,8 @@ func processConfig(uCfg *ini.File, cfg *config.Config, paths []string) error {
 	for _, k := range global.KeyStrings() {
 		if f, found := globalOpts[k]; found {
 			f(global, cfg, paths)
+		} else if name, opt, found := ruleOption(k); found {
+			addRuleOption("*", name, opt, global.Key(k).String(), cfg)
 		} else {
 			cfg.GChecks[k] = validateLevel(k, global.Key(k).String(), cfg)
 			cfg.Checks = append(cfg.Checks, k)
@@ -330,6 +440,8 @@ func processConfig(uCfg *ini.File, cfg *config.Config, paths []string) error {
 				if err = f(sec, uCfg.Section(sec), cfg); err != nil {
 					return err
 				}
+			} else if name, opt, found := ruleOption(k); found {
+				addRuleOption(sec, name, opt, uCfg.Section(sec).Key(k).String(), cfg)
 			} else {
 				syntaxMap[k] = validateLevel(k, uCfg.Section(sec).Key(k).String(), cfg)
 				cfg.Checks = append(cfg.Checks, k)
@@ -0,0 +1,49 @@
+package source
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/errata-ai/vale/v2/config"
+)
+
+func TestParserSections(t *testing.T) {
+	for _, tc := range []struct {
+		section string
+		err     string
+	}{
+		{"[*.adoc]\nParser = asciidoctor\n", ""},
+		{"[*.{md,rst}]\nParser = rst2html\n", ""},
+		{"[*]\nParser = asciidoctor\n", ""},
+		{"[docs/*]\nParser = rst2html\n", ""},
+		{"[formats]\nasc = adoc\n\n[*.asc]\nParser = asciidoctor\n", ""},
+		{"[*.dsl]\nCommand = dsl2html\nParser = markdown\n", ""},
+		{"[*.md]\nParser = asciidoctor\n", "Parser 'asciidoctor' only applies to '.adoc' files."},
+		{"[*.adoc]\nParser = rst2html\n", "Parser 'rst2html' only applies to '.rst' files."},
+		{"[*.adoc]\nParser = asciidoctor\nCommand = adoc2html\n", "Parser 'asciidoctor' can't be used with a Command."},
+		{"[*.rst]\nCommand = rst2html\nParser = rst2html\n", "Parser 'rst2html' can't be used with a Command."},
+	} {
+		dir := t.TempDir()
+		if err := os.MkdirAll(filepath.Join(dir, "styles"), 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		path := filepath.Join(dir, ".vale.ini")
+		writeConfig(t, path, "StylesPath = styles\n\n"+tc.section)
+
+		cfg, err := config.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		cfg.Path = path
+
+		err = From("ini", cfg)
+		if tc.err == "" && err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.section, err)
+		} else if tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)) {
+			t.Errorf("%q: expected = %q, got = %v", tc.section, tc.err, err)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package source
+
+import (
+	"path/filepath"
+
+	"github.com/errata-ai/vale/v2/config"
+	"github.com/errata-ai/vale/v2/core"
+	"gopkg.in/ini.v1"
+)
+
+// FindNested returns the path of the configuration file stored in dir, or ""
+// if there isn't one.
+func FindNested(dir string) string {
+	for _, name := range configNames {
+		if name == "" {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		if core.FileExists(path) && !core.IsDir(path) {
+			return path
+		}
+	}
+	return ""
+}
+
+// LoadNested loads a configuration file that's stored in a subdirectory of
+// the project (similar to an `.editorconfig` file).
+//
+// Only the global (`[*]`) and syntax-specific sections are read -- that is,
+// `BasedOnStyles`, rule toggles, and rule levels. Core settings (such as
+// `StylesPath` and `MinAlertLevel`) always come from the root configuration.
+func LoadNested(path string) (*config.Config, error) {
+	cfg, err := config.New()
+	if err != nil {
+		return cfg, core.NewE100("LoadNested", err)
+	}
+	cfg.Path = path
+
+	uCfg, err := shadowLoad(path)
+	if fe, ok := err.(formatError); ok {
+		return cfg, fe.error
+	} else if err != nil {
+		return cfg, core.NewE100(path, err)
+	}
+
+	uCfg, _, err = extend(uCfg, path, []string{})
+	if err != nil {
+		return cfg, err
+	}
+
+	defaults := uCfg.Section(ini.DefaultSection)
+	for _, key := range defaults.KeyStrings() {
+		defaults.DeleteKey(key)
+	}
+	uCfg.DeleteSection("formats")
+
+	return cfg, processConfig(uCfg, cfg, nil)
+}
@@ -10,10 +10,17 @@ import (
 
 // From updates an existing configuration with values from a user-provided
 // source.
+//
+// The "ini" provider searches for all supported file formats (INI, YAML, and
+// TOML), while the "yaml" and "toml" providers only search for their own.
 func From(provider string, cfg *config.Config) error {
 	switch provider {
 	case "ini":
-		return loadINI(cfg)
+		return loadINI(cfg, configNames)
+	case "yaml":
+		return loadINI(cfg, namesWithExt(".yaml", ".yml"))
+	case "toml":
+		return loadINI(cfg, namesWithExt(".toml"))
 	default:
 		return core.NewE100(
 			"source/From", fmt.Errorf("unknown provider '%s'", provider))
@@ -0,0 +1,88 @@
+package source
+
+import (
+	"errors"
+	"io/ioutil"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/errata-ai/vale/v2/core"
+)
+
+// tomlEntries reads the configuration stored in a TOML file:
+//
+//    StylesPath = "styles"
+//    MinAlertLevel = "suggestion"
+//
+//    ["*.md"]
+//    BasedOnStyles = ["Vale", "MyStyle"]
+//    MyStyle.Rule = false
+//
+// Top-level tables are sections. Within a section, TOML reads dotted keys
+// (e.g., `MyStyle.Rule`) as nested tables; we join them back into a single
+// name.
+func tomlEntries(path string) ([]configEntry, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return []configEntry{}, core.NewE100("tomlEntries", err)
+	}
+
+	var doc map[string]interface{}
+
+	md, err := toml.Decode(string(data), &doc)
+	if err != nil {
+		var perr toml.ParseError
+		if errors.As(err, &perr) {
+			return []configEntry{}, core.NewE201FromPosition(perr.Message, path, perr.Line)
+		}
+		return []configEntry{}, core.NewE100("tomlEntries", err)
+	}
+
+	return tomlToEntries(md, doc, path)
+}
+
+// tomlToEntries lists the keys of a decoded TOML document in the order in
+// which they were defined.
+func tomlToEntries(md toml.MetaData, doc map[string]interface{}, path string) ([]configEntry, error) {
+	entries := []configEntry{}
+	for _, key := range md.Keys() {
+		name := key[len(key)-1]
+
+		switch md.Type(key...) {
+		case "Hash":
+			continue
+		case "ArrayHash":
+			return entries, core.NewE201FromTarget(
+				"Arrays of tables aren't supported.", name, path)
+		}
+
+		value, found := tomlLookup(doc, key)
+		values, ok := entryValues(value)
+		if !found || !ok {
+			return entries, core.NewE201FromTarget(invalidValue(key.String()), name, path)
+		}
+
+		entry := configEntry{key: key.String(), values: values}
+		if len(key) > 1 {
+			entry.section = key[0]
+			entry.key = strings.Join(key[1:], ".")
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func tomlLookup(doc map[string]interface{}, key toml.Key) (interface{}, bool) {
+	var value interface{} = doc
+	for _, part := range key {
+		table, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = table[part]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
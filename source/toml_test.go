@@ -0,0 +1,97 @@
+package source
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTOMLEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".vale.toml")
+	writeConfig(t, path, `# A comment
+StylesPath = "styles"
+MinAlertLevel = 'suggestion' # trailing comment
+
+["*.{md,txt}"]
+BasedOnStyles = [
+  "Vale",
+  "MyStyle",
+]
+MyStyle.Rule = false
+"Other.Rule" = "error"
+TokenIgnores = ['\{#[^}]+\}', "a\tb"]
+
+["*.rst"]
+Other.Limit = 3
+`)
+
+	entries, err := tomlEntries(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []configEntry{
+		{"", "StylesPath", []string{"styles"}},
+		{"", "MinAlertLevel", []string{"suggestion"}},
+		{"*.{md,txt}", "BasedOnStyles", []string{"Vale", "MyStyle"}},
+		{"*.{md,txt}", "MyStyle.Rule", []string{"NO"}},
+		{"*.{md,txt}", "Other.Rule", []string{"error"}},
+		{"*.{md,txt}", "TokenIgnores", []string{`\{#[^}]+\}`, "a\tb"}},
+		{"*.rst", "Other.Limit", []string{"3"}},
+	}
+	if !reflect.DeepEqual(entries, expected) {
+		t.Errorf("expected = %v, got = %v", expected, entries)
+	}
+}
+
+func TestTOMLEntriesErrors(t *testing.T) {
+	cases := map[string]string{
+		"StylesPath = styles\n":              ":1:",
+		"a = 1\n\n[b]\nc = \"unterminated\n": ":4:",
+		"a = 1\na = 2\n":                     ":2:",
+		"a = 1\n[[tables]]\nb = 2\n":         ":2:",
+		"a = \"\\d\"\n":                      ":1:",
+		"[b]\nc = [[1], [2]]\n":              ":2:",
+	}
+
+	path := filepath.Join(t.TempDir(), ".vale.toml")
+	for src, line := range cases {
+		writeConfig(t, path, src)
+		if _, err := tomlEntries(path); err == nil {
+			t.Errorf("%q: expected an error", src)
+		} else if !strings.Contains(err.Error(), "E201") || !strings.Contains(err.Error(), line) {
+			t.Errorf("%q: expected an E201 error on line %s, got %v", src, line, err)
+		}
+	}
+}
+
+func TestEntriesToINI(t *testing.T) {
+	data, err := entriesToINI([]configEntry{
+		{"", "StylesPath", []string{"styles"}},
+		{"*.md", "BasedOnStyles", []string{"Vale", "MyStyle"}},
+		{"*.md", "BlockIgnores", []string{`(?s) *({#.*?})`}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := shadowLoad(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v := f.Section("").Key("StylesPath").String(); v != "styles" {
+		t.Errorf("expected = styles, got = %s", v)
+	}
+
+	styles := f.Section("*.md").Key("BasedOnStyles").ValueWithShadows()
+	if !reflect.DeepEqual(styles, []string{"Vale", "MyStyle"}) {
+		t.Errorf("expected = [Vale MyStyle], got = %v", styles)
+	}
+
+	ignores := f.Section("*.md").Key("BlockIgnores").String()
+	if ignores != `(?s) *({#.*?})` {
+		t.Errorf("expected = (?s) *({#.*?}), got = %s", ignores)
+	}
+}
@@ -48,6 +48,32 @@ func validateLevel(key, val string, cfg *config.Config) bool {
 	return true
 }
 
+// ruleOption splits a rule option override (e.g., "Style.Rule.max") into the
+// rule's name ("Style.Rule") and the option ("max").
+func ruleOption(key string) (string, string, bool) {
+	parts := strings.SplitN(key, ".", 3)
+	if len(parts) != 3 || parts[2] == "" {
+		return "", "", false
+	}
+	return parts[0] + "." + parts[1], parts[2], true
+}
+
+// addRuleOption records an override of one of a rule's options for the
+// given section.
+//
+// The rule is also added to `Checks`, which ensures that it's loaded even if
+// its style isn't, but this doesn't enable it.
+func addRuleOption(sec, name, opt, val string, cfg *config.Config) {
+	if _, found := cfg.RuleOpts[sec]; !found {
+		cfg.RuleOpts[sec] = make(config.RuleOptions)
+	}
+	if _, found := cfg.RuleOpts[sec][name]; !found {
+		cfg.RuleOpts[sec][name] = make(map[string]string)
+	}
+	cfg.RuleOpts[sec][name][opt] = val
+	cfg.Checks = append(cfg.Checks, name)
+}
+
 func loadVocab(root string, config *config.Config) error {
 	root = filepath.Join(config.StylesPath, "Vocab", root)
 
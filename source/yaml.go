@@ -0,0 +1,68 @@
+package source
+
+import (
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+
+	"github.com/errata-ai/vale/v2/core"
+	"gopkg.in/yaml.v2"
+)
+
+// yamlEntries reads the configuration stored in a YAML file:
+//
+//    StylesPath: styles
+//    MinAlertLevel: suggestion
+//
+//    "*.md":
+//      BasedOnStyles: [Vale, MyStyle]
+//      MyStyle.Rule: NO
+//
+// Section names that start with a special character (such as `*`) need to be
+// quoted.
+func yamlEntries(path string) ([]configEntry, error) {
+	entries := []configEntry{}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return entries, core.NewE100("yamlEntries", err)
+	}
+
+	var doc yaml.MapSlice
+	if err = yaml.Unmarshal(data, &doc); err != nil {
+		r := regexp.MustCompile(`yaml: line (\d+): (.+)`)
+		if r.MatchString(err.Error()) {
+			groups := r.FindStringSubmatch(err.Error())
+			i, err := strconv.Atoi(groups[1])
+			if err != nil {
+				return entries, core.NewE100("yamlEntries/Atoi", err)
+			}
+			return entries, core.NewE201FromPosition(groups[2], path, i)
+		}
+		return entries, core.NewE100("yamlEntries", err)
+	}
+
+	for _, item := range doc {
+		key := fmt.Sprint(item.Key)
+		if section, ok := item.Value.(yaml.MapSlice); ok {
+			for _, option := range section {
+				name := fmt.Sprint(option.Key)
+				values, ok := entryValues(option.Value)
+				if !ok {
+					return entries, core.NewE201FromTarget(invalidValue(name), name, path)
+				}
+				entries = append(entries, configEntry{key, name, values})
+			}
+			continue
+		}
+
+		values, ok := entryValues(item.Value)
+		if !ok {
+			return entries, core.NewE201FromTarget(invalidValue(key), key, path)
+		}
+		entries = append(entries, configEntry{"", key, values})
+	}
+
+	return entries, nil
+}
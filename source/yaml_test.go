@@ -0,0 +1,68 @@
+package source
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestYAMLEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".vale.yml")
+	writeConfig(t, path, `# A comment
+StylesPath: styles
+MinAlertLevel: suggestion
+
+"*.{md,txt}":
+  BasedOnStyles: [Vale, MyStyle]
+  MyStyle.Rule: NO
+  Other.Rule: error
+  Other.Limit: 3
+  TokenIgnores:
+    - '\{#[^}]+\}'
+    - "a\tb"
+
+"*.rst":
+  BasedOnStyles: Vale
+  Vale.Spelling: YES
+`)
+
+	entries, err := yamlEntries(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []configEntry{
+		{"", "StylesPath", []string{"styles"}},
+		{"", "MinAlertLevel", []string{"suggestion"}},
+		{"*.{md,txt}", "BasedOnStyles", []string{"Vale", "MyStyle"}},
+		{"*.{md,txt}", "MyStyle.Rule", []string{"NO"}},
+		{"*.{md,txt}", "Other.Rule", []string{"error"}},
+		{"*.{md,txt}", "Other.Limit", []string{"3"}},
+		{"*.{md,txt}", "TokenIgnores", []string{`\{#[^}]+\}`, "a\tb"}},
+		{"*.rst", "BasedOnStyles", []string{"Vale"}},
+		{"*.rst", "Vale.Spelling", []string{"YES"}},
+	}
+	if !reflect.DeepEqual(entries, expected) {
+		t.Errorf("expected = %v, got = %v", expected, entries)
+	}
+}
+
+func TestYAMLEntriesErrors(t *testing.T) {
+	cases := map[string]string{
+		"StylesPath: [styles\n":               ":1:",
+		"a: 1\n\"*.md\":\n  b: 1\n c: 2\n":    ":3:",
+		"a: 1\nVocab: [{b: 1}]\n":             ":2:",
+		"\"*.md\":\n  a: 1\n  Rules: [[1]]\n": ":3:",
+	}
+
+	path := filepath.Join(t.TempDir(), ".vale.yml")
+	for src, line := range cases {
+		writeConfig(t, path, src)
+		if _, err := yamlEntries(path); err == nil {
+			t.Errorf("%q: expected an error", src)
+		} else if !strings.Contains(err.Error(), "E201") || !strings.Contains(err.Error(), line) {
+			t.Errorf("%q: expected an E201 error on line %s, got %v", src, line, err)
+		}
+	}
+}
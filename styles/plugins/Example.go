@@ -1,7 +1,15 @@
 // An example plugin showing how to arbitrarily extend Vale via Golang.
 //
+// Plugins run as stand-alone executables, so this file needs to be built
+// before it can be used:
+//
+//    $ go build -o <StylesPath>/plugins/Example Example.go
+//
 // See https://errata-ai.github.io/vale/plugins/ for more information.
 
+//go:build ignore
+// +build ignore
+
 package main
 
 import (
@@ -10,6 +18,10 @@ import (
 	"github.com/errata-ai/vale/v2/core"
 )
 
+func main() {
+	core.ServePlugin(Example())
+}
+
 // Example extends Vale by implementing a custom rule.
 //
 // The name of this function (i.e., "Example") *must* match the name of its
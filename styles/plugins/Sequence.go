@@ -1,7 +1,15 @@
 // A Vale plugin for checking a sequence of words.
 //
+// Plugins run as stand-alone executables, so this file needs to be built
+// before it can be used:
+//
+//    $ go build -o <StylesPath>/plugins/Sequence Sequence.go
+//
 // See https://errata-ai.github.io/vale/plugins/ for more information.
 
+//go:build ignore
+// +build ignore
+
 package main
 
 import (
@@ -13,6 +21,10 @@ import (
 
 var pat = regexp.MustCompile(`dialog \w+`)
 
+func main() {
+	core.ServePlugin(Sequence())
+}
+
 // Sequence extends Vale by implementing a custom rule.
 func Sequence() core.Plugin {
 	return core.Plugin{
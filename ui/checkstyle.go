@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"encoding/xml"
+	"fmt"
+
+	"github.com/errata-ai/vale/v2/core"
+)
+
+// The Checkstyle XML format.
+//
+// See https://checkstyle.org/ for details.
+type checkstyleReport struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Column   int    `xml:"column,attr"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// checkstyleLevels maps Vale's alert levels to Checkstyle's.
+var checkstyleLevels = map[string]string{
+	"suggestion": "info",
+	"warning":    "warning",
+	"error":      "error",
+}
+
+// PrintCheckstyleAlerts prints Alerts as a Checkstyle XML report.
+func PrintCheckstyleAlerts(linted []*core.File) bool {
+	alertCount := 0
+
+	report := checkstyleReport{Version: "8.0", Files: []checkstyleFile{}}
+	for _, f := range linted {
+		file := checkstyleFile{Name: f.Path}
+		for _, a := range withError(f) {
+			if a.Severity == "error" {
+				alertCount++
+			}
+			file.Errors = append(file.Errors, checkstyleError{
+				Line:     a.Line,
+				Column:   a.Span[0],
+				Severity: checkstyleLevels[a.Severity],
+				Message:  a.Message,
+				Source:   a.Check,
+			})
+		}
+		report.Files = append(report.Files, file)
+	}
+
+	fmt.Println(getXML(report))
+	return alertCount != 0
+}
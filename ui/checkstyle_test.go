@@ -0,0 +1,29 @@
+package ui
+
+import "testing"
+
+func TestPrintCheckstyleAlerts(t *testing.T) {
+	var hasErrors bool
+	out := captureStdout(t, func() {
+		hasErrors = PrintCheckstyleAlerts(testFiles())
+	})
+
+	// The file that couldn't be linted is reported as an error of its own.
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<checkstyle version="8.0">
+  <file name="a.md">
+    <error line="1" column="9" severity="error" message="Remove &#39;very&#39;." source="Test.Very"></error>
+    <error line="3" column="1" severity="warning" message="Remove &#39;very&#39;." source="Test.Very"></error>
+  </file>
+  <file name="b.md"></file>
+  <file name="c.xml">
+    <error line="1" column="1" severity="error" message="&#39;xsltproc&#39; failed: exit status 1" source="Vale.Error"></error>
+  </file>
+</checkstyle>
+`
+	if out != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, out)
+	} else if !hasErrors {
+		t.Error("expected hasErrors to be true")
+	}
+}
@@ -22,12 +22,13 @@ func PrintVerboseAlerts(linted []*core.File, wrap bool) bool {
 		warnings += w
 		suggestions += s
 	}
+	failed := printVerboseErrors(linted)
 
 	etotal := fmt.Sprintf("%d %s", errors, pluralize("error", errors))
 	wtotal := fmt.Sprintf("%d %s", warnings, pluralize("warning", warnings))
 	stotal := fmt.Sprintf("%d %s", suggestions, pluralize("suggestion", suggestions))
 
-	if errors > 0 || warnings > 0 {
+	if errors > 0 || warnings > 0 || failed > 0 {
 		symbol = "\u2716"
 	} else {
 		symbol = "\u2714"
@@ -47,6 +48,25 @@ func PrintVerboseAlerts(linted []*core.File, wrap bool) bool {
 	return errors != 0
 }
 
+// printVerboseErrors lists the files that couldn't be linted (and why).
+func printVerboseErrors(linted []*core.File) int {
+	failed := CountFailed(linted)
+	if failed == 0 {
+		return 0
+	}
+
+	fmt.Printf("\n %s\n\n", aurora.Red(fmt.Sprintf(
+		"%d %s couldn't be linted:", failed, pluralize("file", failed))))
+	for _, f := range linted {
+		if f.Error != "" {
+			fmt.Printf(" %s: %s\n", aurora.Underline(f.Path), f.Error)
+		}
+	}
+	fmt.Println()
+
+	return failed
+}
+
 // printVerboseAlert includes an alert's line, column, level, and message.
 func printVerboseAlert(f *core.File, wrap bool) (int, int, int) {
 	var loc, level string
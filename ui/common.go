@@ -3,18 +3,28 @@ package ui
 import (
 	"sort"
 
+	"github.com/errata-ai/vale/v2/check"
 	"github.com/errata-ai/vale/v2/config"
 	"github.com/errata-ai/vale/v2/core"
 )
 
 // PrintAlerts prints the given alerts in the user-specified format.
-func PrintAlerts(linted []*core.File, config *config.Config) (bool, error) {
+//
+// `rules` are all of the rules that were loaded for the lint, which some
+// formats (such as SARIF) include in their output.
+func PrintAlerts(linted []*core.File, config *config.Config, rules map[string]check.Rule) (bool, error) {
 	if config.Sorted {
 		sort.Sort(core.ByName(linted))
 	}
 	switch config.Output {
 	case "JSON":
 		return PrintJSONAlerts(linted), nil
+	case "sarif":
+		return PrintSARIFAlerts(linted, rules), nil
+	case "junit":
+		return PrintJUnitAlerts(linted), nil
+	case "checkstyle":
+		return PrintCheckstyleAlerts(linted), nil
 	case "line":
 		return PrintLineAlerts(linted, config.Relative), nil
 	case "CLI":
@@ -16,6 +16,7 @@ import (
 // ProcessedFile represents a file that Vale has linted.
 type ProcessedFile struct {
 	Alerts []core.Alert
+	Error  string // why the file couldn't be linted (if it couldn't)
 	Path   string
 }
 
@@ -76,7 +77,7 @@ func PrintCustomAlerts(linted []*core.File, path string) (bool, error) {
 
 	formatted := []ProcessedFile{}
 	for _, f := range linted {
-		if len(f.Alerts) == 0 {
+		if len(f.Alerts) == 0 && f.Error == "" {
 			continue
 		}
 		for _, a := range f.SortedAlerts() {
@@ -88,6 +89,7 @@ func PrintCustomAlerts(linted []*core.File, path string) (bool, error) {
 		formatted = append(formatted, ProcessedFile{
 			Path:   f.Path,
 			Alerts: f.Alerts,
+			Error:  f.Error,
 		})
 	}
 
@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change.
+const diffContext = 3
+
+// PrintDiff writes a unified diff of before -> after to out.
+//
+// The fixes made by Vale never add or remove lines, so we can compare the two
+// versions line-by-line.
+func PrintDiff(out io.Writer, path, before, after string) {
+	old := strings.SplitAfter(before, "\n")
+	cur := strings.SplitAfter(after, "\n")
+	if len(old) != len(cur) {
+		return
+	}
+
+	changed := []int{}
+	for i := range old {
+		if old[i] != cur[i] {
+			changed = append(changed, i)
+		}
+	}
+
+	if len(changed) == 0 {
+		return
+	}
+
+	fmt.Fprintf(out, "--- a/%s\n+++ b/%s\n", path, path)
+	for len(changed) > 0 {
+		// Group all changes that are close enough to share context.
+		end := 1
+		for end < len(changed) && changed[end]-changed[end-1] <= 2*diffContext {
+			end++
+		}
+		hunk := changed[:end]
+		changed = changed[end:]
+
+		first := hunk[0] - diffContext
+		if first < 0 {
+			first = 0
+		}
+		last := hunk[len(hunk)-1] + diffContext
+		if last > len(old)-1 {
+			last = len(old) - 1
+		}
+		if old[last] == "" {
+			// The empty string after a trailing newline isn't a line.
+			last--
+		}
+
+		size := last - first + 1
+		fmt.Fprintf(out, "@@ -%d,%d +%d,%d @@\n", first+1, size, first+1, size)
+
+		for i := first; i <= last; {
+			if old[i] == cur[i] {
+				writeDiffLine(out, " ", old[i])
+				i++
+				continue
+			}
+			j := i
+			for j <= last && old[j] != cur[j] {
+				j++
+			}
+			for k := i; k < j; k++ {
+				writeDiffLine(out, "-", old[k])
+			}
+			for k := i; k < j; k++ {
+				writeDiffLine(out, "+", cur[k])
+			}
+			i = j
+		}
+	}
+}
+
+func writeDiffLine(out io.Writer, prefix, line string) {
+	fmt.Fprint(out, prefix+line)
+	if !strings.HasSuffix(line, "\n") {
+		fmt.Fprint(out, "\n\\ No newline at end of file\n")
+	}
+}
@@ -6,17 +6,47 @@ import (
 	"github.com/errata-ai/vale/v2/core"
 )
 
-// PrintJSONAlerts prints Alerts in map[file.path][]Alert form.
+// jsonErrorsKey is the top-level key under which `PrintJSONAlerts` lists the
+// files that couldn't be linted.
+const jsonErrorsKey = "errors"
+
+// PrintJSONAlerts prints Alerts in map[file.path][]Alert form:
+//
+//    {
+//      "a.md": [{"Check": "Vale.Spelling", ...}],
+//      "errors": {"b.xml": "'xsltproc' failed: exit status 1"}
+//    }
+//
+// The "errors" key is only present if some files couldn't be linted, in
+// which case it maps each of their paths to the reason why. Since every other
+// key is a path, a file that's actually named "errors" is listed as
+// "./errors" instead.
 func PrintJSONAlerts(linted []*core.File) bool {
 	alertCount := 0
-	formatted := map[string][]core.Alert{}
+	formatted := map[string]interface{}{}
+	errors := map[string]string{}
 	for _, f := range linted {
+		path := f.Path
+		if path == jsonErrorsKey {
+			path = "./" + path
+		}
+
+		alerts := []core.Alert{}
 		for _, a := range f.SortedAlerts() {
 			if a.Severity == "error" {
 				alertCount++
 			}
-			formatted[f.Path] = append(formatted[f.Path], a)
+			alerts = append(alerts, a)
 		}
+		if len(alerts) > 0 {
+			formatted[path] = alerts
+		}
+		if f.Error != "" {
+			errors[path] = f.Error
+		}
+	}
+	if len(errors) > 0 {
+		formatted[jsonErrorsKey] = errors
 	}
 	fmt.Println(getJSON(formatted))
 	return alertCount != 0
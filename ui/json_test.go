@@ -0,0 +1,102 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/errata-ai/vale/v2/core"
+)
+
+func TestPrintJSONAlerts(t *testing.T) {
+	var hasErrors bool
+	out := captureStdout(t, func() {
+		hasErrors = PrintJSONAlerts(testFiles())
+	})
+
+	expected := `{
+  "a.md": [
+    {
+      "Action": {
+        "Name": "",
+        "Params": null
+      },
+      "Check": "Test.Very",
+      "Description": "",
+      "Line": 1,
+      "Link": "",
+      "Message": "Remove 'very'.",
+      "Severity": "error",
+      "Span": [
+        9,
+        12
+      ],
+      "Match": "very"
+    },
+    {
+      "Action": {
+        "Name": "",
+        "Params": null
+      },
+      "Check": "Test.Very",
+      "Description": "",
+      "Line": 3,
+      "Link": "",
+      "Message": "Remove 'very'.",
+      "Severity": "warning",
+      "Span": [
+        1,
+        4
+      ],
+      "Match": "Very"
+    }
+  ],
+  "errors": {
+    "c.xml": "'xsltproc' failed: exit status 1"
+  }
+}
+`
+	if out != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, out)
+	} else if !hasErrors {
+		t.Error("expected hasErrors to be true")
+	}
+}
+
+func TestPrintJSONAlertsErrorsFile(t *testing.T) {
+	out := captureStdout(t, func() {
+		PrintJSONAlerts([]*core.File{
+			{Path: "errors", Alerts: []core.Alert{
+				{Check: "Test.Very", Severity: "warning", Line: 1, Span: []int{1, 4}},
+			}},
+			{Path: "c.xml", Error: "'xsltproc' failed: exit status 1"},
+		})
+	})
+
+	expected := `{
+  "./errors": [
+    {
+      "Action": {
+        "Name": "",
+        "Params": null
+      },
+      "Check": "Test.Very",
+      "Description": "",
+      "Line": 1,
+      "Link": "",
+      "Message": "",
+      "Severity": "warning",
+      "Span": [
+        1,
+        4
+      ],
+      "Match": ""
+    }
+  ],
+  "errors": {
+    "c.xml": "'xsltproc' failed: exit status 1"
+  }
+}
+`
+	if out != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, out)
+	}
+}
@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"encoding/xml"
+	"fmt"
+
+	"github.com/errata-ai/vale/v2/core"
+)
+
+// The subset of the (Ant-style) JUnit XML format understood by most CI
+// servers, such as Jenkins and GitLab.
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Errors   int             `xml:"errors,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Error     *junitFailure `xml:"error,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// PrintJUnitAlerts prints Alerts as a JUnit XML report.
+//
+// Each file is a `testsuite` and each Alert is a failing `testcase` named
+// after its check. Files without any Alerts are reported as a single passing
+// `testcase`, which keeps CI servers from treating them as missing.
+func PrintJUnitAlerts(linted []*core.File) bool {
+	alertCount := 0
+
+	report := junitTestSuites{Name: "Vale", Suites: []junitTestSuite{}}
+	for _, f := range linted {
+		suite := junitTestSuite{Name: f.Path}
+		for _, a := range f.SortedAlerts() {
+			if a.Severity == "error" {
+				alertCount++
+			}
+			suite.Cases = append(suite.Cases, junitTestCase{
+				Name:      a.Check,
+				ClassName: fmt.Sprintf("%s:%d:%d", f.Path, a.Line, a.Span[0]),
+				Failure: &junitFailure{
+					Message: a.Message,
+					Type:    a.Severity,
+					Text: fmt.Sprintf("%s:%d:%d: %s [%s] %s",
+						f.Path, a.Line, a.Span[0], a.Severity, a.Check, a.Message),
+				},
+			})
+		}
+
+		suite.Failures = len(suite.Cases)
+		if f.Error != "" {
+			// The file couldn't be linted.
+			suite.Cases = append(suite.Cases, junitTestCase{
+				Name:      "Vale",
+				ClassName: f.Path,
+				Error: &junitFailure{
+					Message: f.Error,
+					Type:    "error",
+					Text:    fmt.Sprintf("%s: %s", f.Path, f.Error),
+				},
+			})
+			suite.Errors = 1
+		} else if suite.Failures == 0 {
+			suite.Cases = append(suite.Cases, junitTestCase{
+				Name:      "Vale",
+				ClassName: f.Path,
+			})
+		}
+		suite.Tests = len(suite.Cases)
+
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Errors += suite.Errors
+		report.Suites = append(report.Suites, suite)
+	}
+
+	fmt.Println(getXML(report))
+	return alertCount != 0
+}
@@ -0,0 +1,36 @@
+package ui
+
+import "testing"
+
+func TestPrintJUnitAlerts(t *testing.T) {
+	var hasErrors bool
+	out := captureStdout(t, func() {
+		hasErrors = PrintJUnitAlerts(testFiles())
+	})
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="Vale" tests="4" failures="2" errors="1">
+  <testsuite name="a.md" tests="2" failures="2" errors="0">
+    <testcase name="Test.Very" classname="a.md:1:9">
+      <failure message="Remove &#39;very&#39;." type="error">a.md:1:9: error [Test.Very] Remove &#39;very&#39;.</failure>
+    </testcase>
+    <testcase name="Test.Very" classname="a.md:3:1">
+      <failure message="Remove &#39;very&#39;." type="warning">a.md:3:1: warning [Test.Very] Remove &#39;very&#39;.</failure>
+    </testcase>
+  </testsuite>
+  <testsuite name="b.md" tests="1" failures="0" errors="0">
+    <testcase name="Vale" classname="b.md"></testcase>
+  </testsuite>
+  <testsuite name="c.xml" tests="1" failures="0" errors="1">
+    <testcase name="Vale" classname="c.xml">
+      <error message="&#39;xsltproc&#39; failed: exit status 1" type="error">c.xml: &#39;xsltproc&#39; failed: exit status 1</error>
+    </testcase>
+  </testsuite>
+</testsuites>
+`
+	if out != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, out)
+	} else if !hasErrors {
+		t.Error("expected hasErrors to be true")
+	}
+}
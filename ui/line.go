@@ -29,7 +29,7 @@ func PrintLineAlerts(linted []*core.File, relative bool) bool {
 			base = f.Path
 		}
 
-		for _, a := range f.SortedAlerts() {
+		for _, a := range withError(f) {
 			if a.Severity == "error" {
 				alertCount++
 			}
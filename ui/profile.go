@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/errata-ai/vale/v2/lint"
+)
+
+const (
+	// slowPatternShare is the portion of a rule's run time that its pattern
+	// has to account for before we flag it.
+	slowPatternShare = 0.5
+	// slowRuleShare is the portion of the total run time (of all rules) that
+	// a rule has to account for before we flag it.
+	slowRuleShare = 0.1
+	// slowPatternTime is the time that a rule has to spend matching its
+	// pattern before we flag it: without it, the only rule of a quick run
+	// (e.g., 30µs) would always account for all of the total.
+	slowPatternTime = 50 * time.Millisecond
+)
+
+// PrintProfile writes a table of per-rule statistics to out, slowest first.
+//
+// Rules whose pattern accounts for most of their own run time -- and whose
+// run time is a significant part of the total -- are flagged as having a
+// slow pattern (provided that matching it took a noticeable amount of time).
+func PrintProfile(out io.Writer, timings *lint.Timings) {
+	if timings == nil {
+		return
+	}
+
+	rules := timings.Rules()
+
+	var total time.Duration
+	for _, t := range rules {
+		total += t.Duration
+	}
+
+	flagged := []lint.Timing{}
+
+	table := newTable(out, "Rule", "Runs", "Alerts", "Total", "Average", "Pattern", "")
+	for _, t := range rules {
+		avg := time.Duration(0)
+		if t.Count > 0 {
+			avg = t.Duration / time.Duration(t.Count)
+		}
+
+		pattern, note := "-", ""
+		if t.Pattern != "" {
+			pattern = fmt.Sprintf("%s (%d%%)", formatDuration(t.PatternTime), percent(t.PatternTime, t.Duration))
+			if isSlowPattern(t, total) {
+				note = "slow pattern"
+				flagged = append(flagged, t)
+			}
+		}
+
+		table.Append([]string{
+			t.Name, strconv.Itoa(t.Count), strconv.Itoa(t.Alerts),
+			formatDuration(t.Duration), formatDuration(avg), pattern, note})
+	}
+	table.Render()
+
+	fmt.Fprintf(out, "\n%d %s took %s in total.\n",
+		len(rules), pluralize("rule", len(rules)), formatDuration(total))
+
+	for _, t := range flagged {
+		fmt.Fprintf(out,
+			"\n'%s' spends %d%% of its time matching its %d-byte pattern and accounts for %d%% of the total.\n",
+			t.Name, percent(t.PatternTime, t.Duration), len(t.Pattern), percent(t.Duration, total))
+	}
+}
+
+func isSlowPattern(t lint.Timing, total time.Duration) bool {
+	if total == 0 || t.Duration == 0 || t.PatternTime < slowPatternTime {
+		return false
+	}
+	share := float64(t.Duration) / float64(total)
+	patternShare := float64(t.PatternTime) / float64(t.Duration)
+	return share >= slowRuleShare && patternShare >= slowPatternShare
+}
+
+func percent(part, whole time.Duration) int {
+	if whole == 0 {
+		return 0
+	}
+	return int(100 * float64(part) / float64(whole))
+}
@@ -0,0 +1,33 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/errata-ai/vale/v2/lint"
+)
+
+func TestIsSlowPattern(t *testing.T) {
+	cases := []struct {
+		timing lint.Timing
+		total  time.Duration
+		slow   bool
+	}{
+		// A cheap rule that happens to be the only one.
+		{lint.Timing{Duration: 30 * time.Microsecond, PatternTime: 25 * time.Microsecond},
+			30 * time.Microsecond, false},
+		{lint.Timing{Duration: 2 * time.Second, PatternTime: 1500 * time.Millisecond},
+			3 * time.Second, true},
+		// Most of the time is spent elsewhere in the rule.
+		{lint.Timing{Duration: 2 * time.Second, PatternTime: 500 * time.Millisecond},
+			3 * time.Second, false},
+		// The rule is a small part of the total.
+		{lint.Timing{Duration: 200 * time.Millisecond, PatternTime: 150 * time.Millisecond},
+			10 * time.Second, false},
+	}
+	for _, tc := range cases {
+		if slow := isSlowPattern(tc.timing, tc.total); slow != tc.slow {
+			t.Errorf("%v of %v: expected = %v, got = %v", tc.timing, tc.total, tc.slow, slow)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/errata-ai/vale/v2/lint"
+)
+
+// PrintRules writes an explanation of the rules that apply to a file to out.
+func PrintRules(out io.Writer, exp *lint.Explanation, format string) {
+	if format == "JSON" {
+		fmt.Fprintln(out, getJSON(exp))
+		return
+	}
+
+	fmt.Fprintf(out, "File: %s\n", exp.Path)
+	fmt.Fprintf(out, "Sections: %s\n", listOrNone(exp.Sections))
+	fmt.Fprintf(out, "BasedOnStyles: %s\n\n", listOrNone(exp.BaseStyles))
+
+	table := newTable(out, "Rule", "Runs", "Level", "Scope", "Reason")
+	for _, r := range exp.Rules {
+		runs := "no"
+		if r.Runs {
+			runs = "yes"
+		}
+		table.Append([]string{r.Name, runs, r.Level, r.Scope, r.Reason})
+	}
+	table.Render()
+}
+
+func listOrNone(items []string) string {
+	if len(items) == 0 {
+		return "(none)"
+	}
+	return strings.Join(items, ", ")
+}
@@ -0,0 +1,244 @@
+package ui
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/errata-ai/vale/v2/check"
+	"github.com/errata-ai/vale/v2/core"
+)
+
+// The subset of SARIF 2.1.0 that we produce.
+//
+// See https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool        sarifTool         `json:"tool"`
+	Invocations []sarifInvocation `json:"invocations,omitempty"`
+	Results     []sarifResult     `json:"results"`
+}
+
+type sarifInvocation struct {
+	ExecutionSuccessful        bool                `json:"executionSuccessful"`
+	ToolExecutionNotifications []sarifNotification `json:"toolExecutionNotifications"`
+}
+
+type sarifNotification struct {
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string                `json:"name"`
+	InformationURI string                `json:"informationUri"`
+	Rules          []sarifReportingDescr `json:"rules"`
+}
+
+type sarifReportingDescr struct {
+	ID                   string             `json:"id"`
+	ShortDescription     *sarifMessage      `json:"shortDescription,omitempty"`
+	FullDescription      *sarifMessage      `json:"fullDescription,omitempty"`
+	HelpURI              string             `json:"helpUri,omitempty"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+	EndColumn   int `json:"endColumn"`
+}
+
+// sarifLevels maps Vale's alert levels to SARIF's.
+var sarifLevels = map[string]string{
+	"suggestion": "note",
+	"warning":    "warning",
+	"error":      "error",
+}
+
+// PrintSARIFAlerts prints Alerts as a SARIF 2.1.0 log, including a
+// `reportingDescriptor` for each of the given rules.
+func PrintSARIFAlerts(linted []*core.File, rules map[string]check.Rule) bool {
+	alertCount := 0
+
+	names := []string{}
+	for name := range rules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	driver := sarifDriver{
+		Name:           "Vale",
+		InformationURI: "https://github.com/errata-ai/vale",
+		Rules:          []sarifReportingDescr{},
+	}
+
+	index := map[string]int{}
+	for _, name := range names {
+		index[name] = len(driver.Rules)
+		driver.Rules = append(driver.Rules, toDescriptor(name, rules[name].Fields()))
+	}
+
+	results := []sarifResult{}
+	for _, f := range linted {
+		uri := sarifURI(f.Path)
+		for _, a := range f.SortedAlerts() {
+			if a.Severity == "error" {
+				alertCount++
+			}
+
+			idx, found := index[a.Check]
+			if !found {
+				// Some rules (e.g., LanguageTool) report alerts under
+				// a name that doesn't correspond to a loaded rule.
+				idx = len(driver.Rules)
+				index[a.Check] = idx
+				driver.Rules = append(driver.Rules, toDescriptor(a.Check, check.Definition{
+					Level: a.Severity,
+					Link:  a.Link,
+				}))
+			}
+
+			results = append(results, sarifResult{
+				RuleID:    a.Check,
+				RuleIndex: idx,
+				Level:     sarifLevels[a.Severity],
+				Message:   sarifMessage{Text: a.Message},
+				Locations: []sarifLocation{{
+					PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: sarifArtifactLocation{URI: uri},
+						Region: sarifRegion{
+							StartLine:   a.Line,
+							StartColumn: a.Span[0],
+							EndColumn:   a.Span[1] + 1,
+						},
+					},
+				}},
+			})
+		}
+	}
+
+	run := sarifRun{Tool: sarifTool{Driver: driver}, Results: results}
+
+	// The files that couldn't be linted are reported as notifications of an
+	// unsuccessful invocation.
+	notifications := []sarifNotification{}
+	for _, f := range linted {
+		if f.Error == "" {
+			continue
+		}
+		notifications = append(notifications, sarifNotification{
+			Level:   "error",
+			Message: sarifMessage{Text: f.Error},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: sarifURI(f.Path)},
+					Region:           sarifRegion{StartLine: 1, StartColumn: 1, EndColumn: 1},
+				},
+			}},
+		})
+	}
+	if len(notifications) > 0 {
+		run.Invocations = []sarifInvocation{{
+			ExecutionSuccessful:        false,
+			ToolExecutionNotifications: notifications,
+		}}
+	}
+
+	fmt.Println(getJSON(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	}))
+
+	return alertCount != 0
+}
+
+func toDescriptor(name string, def check.Definition) sarifReportingDescr {
+	descr := sarifReportingDescr{
+		ID:                   name,
+		HelpURI:              def.Link,
+		DefaultConfiguration: sarifConfiguration{Level: sarifLevels[def.Level]},
+	}
+
+	if descr.DefaultConfiguration.Level == "" {
+		descr.DefaultConfiguration.Level = "warning"
+	}
+
+	if def.Message != "" && !strings.Contains(def.Message, "%") {
+		// NOTE: Messages that are templates (e.g., "Use '%s' instead.") don't
+		// make for useful descriptions.
+		descr.ShortDescription = &sarifMessage{Text: core.WhitespaceToSpace(def.Message)}
+	}
+	if def.Description != "" {
+		descr.FullDescription = &sarifMessage{Text: core.WhitespaceToSpace(def.Description)}
+	}
+
+	return descr
+}
+
+// sarifURI converts path into a URI: a relative reference for a file beneath
+// the current directory and a `file://` URI for anything else.
+func sarifURI(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return (&url.URL{Path: filepath.ToSlash(path)}).String()
+	}
+
+	if cwd, err := os.Getwd(); err == nil {
+		rel, err := filepath.Rel(cwd, abs)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return (&url.URL{Path: filepath.ToSlash(rel)}).String()
+		}
+	}
+
+	// NOTE: On Windows, the path has a drive letter (e.g., "C:/...") that
+	// needs a leading slash: "file:///C:/...".
+	abs = filepath.ToSlash(abs)
+	if !strings.HasPrefix(abs, "/") {
+		abs = "/" + abs
+	}
+	return (&url.URL{Scheme: "file", Path: abs}).String()
+}
@@ -0,0 +1,146 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestPrintSARIFAlerts(t *testing.T) {
+	var hasErrors bool
+	out := captureStdout(t, func() {
+		hasErrors = PrintSARIFAlerts(testFiles(), testRules)
+	})
+
+	expected := `{
+  "$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+  "version": "2.1.0",
+  "runs": [
+    {
+      "tool": {
+        "driver": {
+          "name": "Vale",
+          "informationUri": "https://github.com/errata-ai/vale",
+          "rules": [
+            {
+              "id": "Test.Very",
+              "shortDescription": {
+                "text": "Remove 'very'."
+              },
+              "helpUri": "https://example.com/very",
+              "defaultConfiguration": {
+                "level": "error"
+              }
+            }
+          ]
+        }
+      },
+      "invocations": [
+        {
+          "executionSuccessful": false,
+          "toolExecutionNotifications": [
+            {
+              "level": "error",
+              "message": {
+                "text": "'xsltproc' failed: exit status 1"
+              },
+              "locations": [
+                {
+                  "physicalLocation": {
+                    "artifactLocation": {
+                      "uri": "c.xml"
+                    },
+                    "region": {
+                      "startLine": 1,
+                      "startColumn": 1,
+                      "endColumn": 1
+                    }
+                  }
+                }
+              ]
+            }
+          ]
+        }
+      ],
+      "results": [
+        {
+          "ruleId": "Test.Very",
+          "ruleIndex": 0,
+          "level": "error",
+          "message": {
+            "text": "Remove 'very'."
+          },
+          "locations": [
+            {
+              "physicalLocation": {
+                "artifactLocation": {
+                  "uri": "a.md"
+                },
+                "region": {
+                  "startLine": 1,
+                  "startColumn": 9,
+                  "endColumn": 13
+                }
+              }
+            }
+          ]
+        },
+        {
+          "ruleId": "Test.Very",
+          "ruleIndex": 0,
+          "level": "warning",
+          "message": {
+            "text": "Remove 'very'."
+          },
+          "locations": [
+            {
+              "physicalLocation": {
+                "artifactLocation": {
+                  "uri": "a.md"
+                },
+                "region": {
+                  "startLine": 3,
+                  "startColumn": 1,
+                  "endColumn": 5
+                }
+              }
+            }
+          ]
+        }
+      ]
+    }
+  ]
+}
+`
+	if out != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, out)
+	} else if !hasErrors {
+		t.Error("expected hasErrors to be true")
+	}
+}
+
+func TestSARIFURI(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outside := filepath.Join(filepath.Dir(cwd), "other", "a.md")
+	expected := "file://" + filepath.ToSlash(outside)
+	if runtime.GOOS == "windows" {
+		expected = "file:///" + filepath.ToSlash(outside)
+	}
+
+	for path, uri := range map[string]string{
+		"a.md":                               "a.md",
+		filepath.Join("docs", "a b.md"):      "docs/a%20b.md",
+		filepath.Join(cwd, "a.md"):           "a.md",
+		outside:                              expected,
+		filepath.Join("..", "other", "a.md"): expected,
+	} {
+		if got := sarifURI(path); got != uri {
+			t.Errorf("%s: expected = %s, got = %s", path, uri, got)
+		}
+	}
+}
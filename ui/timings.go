@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/errata-ai/vale/v2/lint"
+)
+
+// maxTimings is the number of files shown in a timings report.
+const maxTimings = 20
+
+// PrintTimings writes a report of the slowest files and rules to out.
+func PrintTimings(out io.Writer, timings *lint.Timings) {
+	if timings == nil {
+		return
+	}
+
+	table := newTable(out, "File", "Time")
+	files := timings.Files()
+	for i, t := range files {
+		if i == maxTimings {
+			table.Append([]string{fmt.Sprintf("... (%d more)", len(files)-maxTimings), ""})
+			break
+		}
+		table.Append([]string{t.Name, formatDuration(t.Duration)})
+	}
+	table.Render()
+
+	fmt.Fprintln(out)
+
+	table = newTable(out, "Rule", "Time", "Runs")
+	for _, t := range timings.Rules() {
+		table.Append([]string{t.Name, formatDuration(t.Duration), strconv.Itoa(t.Count)})
+	}
+	table.Render()
+}
+
+func formatDuration(d time.Duration) string {
+	if d >= time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(10 * time.Microsecond).String()
+}
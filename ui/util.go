@@ -1,6 +1,55 @@
 package ui
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+
+	"github.com/errata-ai/vale/v2/core"
+	"github.com/olekukonko/tablewriter"
+)
+
+// withError returns f's sorted alerts and, if f couldn't be linted, an
+// "error" that explains why.
+//
+// This is how formats without a separate notion of errors report them.
+func withError(f *core.File) []core.Alert {
+	alerts := f.SortedAlerts()
+	if f.Error != "" {
+		alerts = append([]core.Alert{{
+			Check: "Vale.Error", Severity: "error", Line: 1, Span: []int{1, 1},
+			Message: f.Error}}, alerts...)
+	}
+	return alerts
+}
+
+// CountFailed returns the number of files that couldn't be linted.
+func CountFailed(linted []*core.File) int {
+	failed := 0
+	for _, f := range linted {
+		if f.Error != "" {
+			failed++
+		}
+	}
+	return failed
+}
+
+// newTable creates a borderless table with the given header, in the style of
+// our alert listings.
+func newTable(out io.Writer, header ...string) *tablewriter.Table {
+	table := tablewriter.NewWriter(out)
+	table.SetHeader(header)
+	table.SetAutoFormatHeaders(false)
+	table.SetAutoWrapText(false)
+	table.SetBorder(false)
+	table.SetHeaderLine(false)
+	table.SetCenterSeparator("")
+	table.SetColumnSeparator("")
+	table.SetRowSeparator("")
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	return table
+}
 
 func pluralize(s string, n int) string {
 	if n != 1 {
@@ -16,3 +65,11 @@ func getJSON(data interface{}) string {
 	}
 	return string(b)
 }
+
+func getXML(data interface{}) string {
+	b, err := xml.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return xml.Header + string(b)
+}
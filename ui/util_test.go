@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/errata-ai/vale/v2/check"
+	"github.com/errata-ai/vale/v2/core"
+)
+
+// stubRule is a rule that only provides its definition.
+type stubRule struct {
+	def check.Definition
+}
+
+func (r stubRule) Run(text string, file *core.File) ([]core.Alert, error) {
+	return nil, nil
+}
+
+func (r stubRule) Fields() check.Definition {
+	return r.def
+}
+
+func (r stubRule) Pattern() string {
+	return ""
+}
+
+var testRules = map[string]check.Rule{
+	"Test.Very": stubRule{def: check.Definition{
+		Name:    "Test.Very",
+		Level:   "error",
+		Message: "Remove 'very'.",
+		Link:    "https://example.com/very",
+	}},
+}
+
+// testFiles returns a file with alerts, a file without any, and a file that
+// couldn't be linted.
+func testFiles() []*core.File {
+	return []*core.File{
+		{Path: "a.md", Alerts: []core.Alert{
+			{Check: "Test.Very", Severity: "warning", Line: 3, Span: []int{1, 4},
+				Message: "Remove 'very'.", Match: "Very"},
+			{Check: "Test.Very", Severity: "error", Line: 1, Span: []int{9, 12},
+				Message: "Remove 'very'.", Match: "very"},
+		}},
+		{Path: "b.md"},
+		{Path: "c.xml", Error: "'xsltproc' failed: exit status 1"},
+	}
+}
+
+// captureStdout returns everything that print writes to stdout.
+func captureStdout(t *testing.T, print func()) string {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	done := make(chan string)
+	go func() {
+		var buf bytes.Buffer
+		io.Copy(&buf, r)
+		done <- buf.String()
+	}()
+
+	print()
+	w.Close()
+
+	return <-done
+}
+
+func TestCountFailed(t *testing.T) {
+	if n := CountFailed(testFiles()); n != 1 {
+		t.Errorf("expected = 1, got = %d", n)
+	}
+}
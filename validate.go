@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/errata-ai/vale/v2/check"
+	"github.com/errata-ai/vale/v2/config"
+	"github.com/errata-ai/vale/v2/core"
+	"github.com/errata-ai/vale/v2/ui"
+)
+
+// validateStyles checks every rule definition (i.e., `.yml` file) stored in
+// the given StylesPath, reporting any errors in the configured format.
+//
+// We return the number of invalid rules.
+func validateStyles(root string, cfg *config.Config) (int, error) {
+	if !core.IsDir(root) {
+		return 0, core.NewE100(
+			"validate",
+			fmt.Errorf("'%s' is not a directory", root))
+	}
+
+	rules, invalid := 0, 0
+	err := filepath.Walk(root, func(fp string, fi os.FileInfo, err error) error {
+		if err != nil || fi.IsDir() || filepath.Ext(fp) != ".yml" {
+			return err
+		}
+		rules++
+
+		errs := check.Validate(fp)
+		for _, e := range errs {
+			ui.ShowError(e, cfg.Output, os.Stdout)
+		}
+		if len(errs) > 0 {
+			invalid++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return invalid, core.NewE100("validate", err)
+	}
+
+	if cfg.Output == "CLI" {
+		fmt.Printf("%d of %d rule(s) in '%s' are invalid.\n", invalid, rules, root)
+	}
+	return invalid, nil
+}